@@ -0,0 +1,247 @@
+package bitkub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// CandleOption configures a CandleAggregator.
+type CandleOption func(*CandleAggregator)
+
+// WithAllowedLateness keeps a candle open for trades until lateness after its end. It defaults to 0,
+// which closes a candle with the first trade of a later candle.
+func WithAllowedLateness(lateness time.Duration) CandleOption {
+	return func(a *CandleAggregator) {
+		a.lateness = lateness
+	}
+}
+
+// CandleAggregator builds candles of any interval, e.g. 10s or 3m, from trades of GetMarketTrades or
+// of a trade stream. Trades are de-duplicated like in MarketStream, so overlapping polls can be added.
+//
+// Time is driven by the trades: a candle closes once a trade at least the allowed lateness after its
+// end was added, or when Advance is called with such a time. Trades of closed candles are rejected.
+// Intervals without trades have no candle. Handlers are called from the goroutine adding the trade.
+type CandleAggregator struct {
+	interval time.Duration
+	lateness time.Duration
+
+	mu          sync.Mutex
+	open        map[time.Time]*model.Candle
+	closedUntil time.Time // end of the last closed candle
+	seededUntil time.Time // trades before are part of the seeded candles
+	cursor      *tradeCursor
+	onUpdate    func(model.Candle)
+	onClose     func(model.Candle)
+}
+
+// NewCandleAggregator creates an aggregator of candles lasting interval. It panics when interval is
+// not positive.
+func NewCandleAggregator(interval time.Duration, opts ...CandleOption) *CandleAggregator {
+	if interval <= 0 {
+		panic(fmt.Sprintf("bitkub: candle interval must be positive, got %v", interval))
+	}
+	a := &CandleAggregator{
+		interval: interval,
+		open:     map[time.Time]*model.Candle{},
+		cursor:   newTradeCursor(),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// OnUpdate sets the handler called with an open candle each time a trade changed it.
+func (a *CandleAggregator) OnUpdate(handler func(model.Candle)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onUpdate = handler
+}
+
+// OnClose sets the handler called once with each candle when it closes, oldest first.
+func (a *CandleAggregator) OnClose(handler func(model.Candle)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onClose = handler
+}
+
+// AddTrade adds a trade of GetMarketTrades. It reports whether the trade was used, false for a
+// duplicate or late trade.
+func (a *CandleAggregator) AddTrade(trade model.MarketTrade) bool {
+	return a.add(marketTradeEvent(trade), true)
+}
+
+// AddTrades adds the trades of GetMarketTrades, which come newest first, oldest first. Unlike with
+// AddTrade, distinct trades of the same call sharing timestamp, rate and amount are all added.
+func (a *CandleAggregator) AddTrades(trades []model.MarketTrade) {
+	sorted := append([]model.MarketTrade{}, trades...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+	for i, trade := range sorted {
+		a.add(marketTradeEvent(trade), i == 0)
+	}
+}
+
+// AddTradeEvent adds a trade of a trade stream, see MarketStream.SubscribeTrades. Trades are
+// de-duplicated by transaction id.
+func (a *CandleAggregator) AddTradeEvent(event model.TradeEvent) bool {
+	return a.add(event, false)
+}
+
+func marketTradeEvent(trade model.MarketTrade) model.TradeEvent {
+	return model.TradeEvent{Rate: trade.Rate, Amount: trade.Amount, Timestamp: int64(trade.Timestamp)}
+}
+
+// Advance closes the candles which ended at least the allowed lateness before now, e.g. on a timer
+// when no trade is made.
+func (a *CandleAggregator) Advance(now time.Time) {
+	a.mu.Lock()
+	closed := a.closeLocked(now)
+	onClose := a.onClose
+	a.mu.Unlock()
+	emitCandles(onClose, closed)
+}
+
+// Open returns the open candles, oldest first.
+func (a *CandleAggregator) Open() []model.Candle {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.sortedLocked(func(*model.Candle) bool { return true })
+}
+
+// add adds a trade, starting a batch of trades without transaction id when batch is set.
+func (a *CandleAggregator) add(event model.TradeEvent, batch bool) bool {
+	at := time.Unix(event.Timestamp, 0).UTC()
+
+	a.mu.Lock()
+	if batch {
+		a.cursor.startBatch()
+	}
+	if at.Before(a.seededUntil) || at.Before(a.closedUntil) || !a.cursor.add(event) {
+		a.mu.Unlock()
+		return false
+	}
+
+	closed := a.closeLocked(at)
+	start := bucketStart(at, a.interval)
+	candle, ok := a.open[start]
+	if !ok {
+		candle = &model.Candle{Time: start, Open: event.Rate, High: event.Rate, Low: event.Rate, Volume: decimal.Zero}
+		a.open[start] = candle
+	}
+	candle.High = decimal.Max(candle.High, event.Rate)
+	candle.Low = decimal.Min(candle.Low, event.Rate)
+	candle.Close = event.Rate
+	candle.Volume = candle.Volume.Add(event.Amount)
+	updated := *candle
+
+	onUpdate, onClose := a.onUpdate, a.onClose
+	a.mu.Unlock()
+
+	emitCandles(onClose, closed)
+	if onUpdate != nil {
+		onUpdate(updated)
+	}
+	return true
+}
+
+// bucketStart returns the start of the candle of interval containing t. Candles are aligned to the
+// Unix epoch like those of GetCandles, whereas time.Truncate aligns them to the zero time.
+func bucketStart(t time.Time, interval time.Duration) time.Time {
+	ns := t.UnixNano()
+	return time.Unix(0, ns-ns%int64(interval)).UTC()
+}
+
+// closeLocked removes the candles which ended at least the allowed lateness before now.
+func (a *CandleAggregator) closeLocked(now time.Time) []model.Candle {
+	closed := a.sortedLocked(func(c *model.Candle) bool {
+		return !c.Time.Add(a.interval + a.lateness).After(now)
+	})
+	for _, candle := range closed {
+		delete(a.open, candle.Time)
+		a.closedUntil = candle.Time.Add(a.interval)
+	}
+	return closed
+}
+
+func (a *CandleAggregator) sortedLocked(keep func(*model.Candle) bool) []model.Candle {
+	candles := []model.Candle{}
+	for _, candle := range a.open {
+		if keep(candle) {
+			candles = append(candles, *candle)
+		}
+	}
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Time.Before(candles[j].Time) })
+	return candles
+}
+
+func emitCandles(handler func(model.Candle), candles []model.Candle) {
+	if handler == nil {
+		return
+	}
+	for _, candle := range candles {
+		handler(candle)
+	}
+}
+
+// Seed joins the aggregator to candles of its interval made from the trades before asOf. Candles
+// still open at asOf continue with later trades, and trades before asOf are rejected.
+func (a *CandleAggregator) Seed(candles []model.Candle, asOf time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, candle := range candles {
+		if !candle.Time.Add(a.interval + a.lateness).After(asOf) {
+			if end := candle.Time.Add(a.interval); end.After(a.closedUntil) {
+				a.closedUntil = end
+			}
+			continue
+		}
+		c := candle
+		a.open[c.Time] = &c
+	}
+	a.seededUntil = asOf
+}
+
+// SeedFromHistory seeds the aggregator with GetCandles of symbol from from until asOf, usually
+// time.Now(), see Seed. The candles of the largest resolution dividing the interval are merged, so
+// the interval must be a multiple of a minute.
+func (a *CandleAggregator) SeedFromHistory(ctx context.Context, api *Client, symbol string, from, asOf time.Time) error {
+	var resolution Resolution
+	for i := len(Resolutions) - 1; i >= 0; i-- {
+		if a.interval%Resolutions[i].Duration() == 0 {
+			resolution = Resolutions[i]
+			break
+		}
+	}
+	if resolution == "" {
+		return fmt.Errorf("no resolution divides the interval %s", a.interval)
+	}
+
+	history, err := api.GetCandlesCtx(ctx, symbol, resolution, from, asOf)
+	if err != nil {
+		return err
+	}
+
+	merged := []model.Candle{}
+	for _, candle := range history {
+		start := bucketStart(candle.Time, a.interval)
+		if n := len(merged); n > 0 && merged[n-1].Time.Equal(start) {
+			last := &merged[n-1]
+			last.High = decimal.Max(last.High, candle.High)
+			last.Low = decimal.Min(last.Low, candle.Low)
+			last.Close = candle.Close
+			last.Volume = last.Volume.Add(candle.Volume)
+			continue
+		}
+		candle.Time = start
+		merged = append(merged, candle)
+	}
+	a.Seed(merged, asOf)
+	return nil
+}
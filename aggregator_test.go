@@ -0,0 +1,152 @@
+package bitkub_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+func candleString(c model.Candle) string {
+	return fmt.Sprintf("%d:%s/%s/%s/%s/%s", c.Time.Unix(), c.Open, c.High, c.Low, c.Close, c.Volume)
+}
+
+func trade(ts int, rate, amount int64) model.MarketTrade {
+	return model.MarketTrade{Timestamp: ts, Rate: decimal.NewFromInt(rate), Amount: decimal.NewFromInt(amount)}
+}
+
+func TestCandleAggregator(t *testing.T) {
+	agg := bitkub.NewCandleAggregator(10*time.Second, bitkub.WithAllowedLateness(5*time.Second))
+	var updated, closed []string
+	agg.OnUpdate(func(c model.Candle) { updated = append(updated, candleString(c)) })
+	agg.OnClose(func(c model.Candle) { closed = append(closed, candleString(c)) })
+
+	// newest first, as returned by GetMarketTrades
+	agg.AddTrades([]model.MarketTrade{trade(112, 103, 1), trade(105, 98, 2), trade(101, 100, 1)})
+	// the next poll overlaps the previous one
+	agg.AddTrades([]model.MarketTrade{trade(113, 104, 1), trade(112, 103, 1)})
+
+	// late, but the candle from 100 is still open until 115
+	if !agg.AddTradeEvent(model.TradeEvent{Rate: decimal.NewFromInt(105), Amount: decimal.NewFromInt(1), Timestamp: 108}) {
+		t.Fatal("expected the late trade within the allowed lateness to be used")
+	}
+	// closes the candle from 100
+	agg.AddTradeEvent(model.TradeEvent{Rate: decimal.NewFromInt(101), Amount: decimal.NewFromInt(1), Timestamp: 116})
+	if agg.AddTrade(trade(109, 99, 1)) {
+		t.Fatal("expected the trade of a closed candle to be rejected")
+	}
+
+	agg.Advance(time.Unix(125, 0))
+
+	wantClosed := []string{"100:100/105/98/105/4", "110:103/104/101/101/3"}
+	if strings.Join(closed, " ") != strings.Join(wantClosed, " ") {
+		t.Fatalf("got closed %v, want %v", closed, wantClosed)
+	}
+	if len(updated) != 6 || updated[1] != "100:100/100/98/98/3" {
+		t.Fatalf("unexpected updates %v", updated)
+	}
+	if open := agg.Open(); len(open) != 0 {
+		t.Fatalf("unexpected open candles %v", open)
+	}
+}
+
+func TestCandleAggregatorAlikeTrades(t *testing.T) {
+	agg := bitkub.NewCandleAggregator(10 * time.Second)
+	event := func(txn string) model.TradeEvent {
+		return model.TradeEvent{TxnID: txn, Rate: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1), Timestamp: 101}
+	}
+
+	// distinct trades of the stream sharing timestamp, rate and amount
+	if !agg.AddTradeEvent(event("A")) || !agg.AddTradeEvent(event("B")) {
+		t.Fatal("expected both trades to be used")
+	}
+	if agg.AddTradeEvent(event("B")) {
+		t.Fatal("expected the same trade to be rejected")
+	}
+	// a poll with both trades and a third one alike
+	agg.AddTrades([]model.MarketTrade{trade(102, 100, 1), trade(101, 100, 1), trade(101, 100, 1), trade(101, 100, 1)})
+	if agg.AddTrade(trade(101, 100, 1)) {
+		t.Fatal("expected a polled trade already added to be rejected")
+	}
+
+	if open := agg.Open(); len(open) != 1 || candleString(open[0]) != "100:100/100/100/100/4" {
+		t.Fatalf("unexpected open candles %v", open)
+	}
+}
+
+func TestCandleAggregatorEpochAligned(t *testing.T) {
+	// 7s does not divide the time from the zero time to the Unix epoch
+	agg := bitkub.NewCandleAggregator(7 * time.Second)
+	agg.AddTrade(trade(100, 100, 1))
+	if open := agg.Open(); len(open) != 1 || open[0].Time.Unix() != 98 {
+		t.Fatalf("unexpected open candles %v", open)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"s":"ok","t":[1700000040],"o":[10],"h":[12],"l":[9],"c":[11],"v":[1]}`)
+	}))
+	defer srv.Close()
+	api := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil))
+
+	agg = bitkub.NewCandleAggregator(7 * time.Minute)
+	if err := agg.SeedFromHistory(context.Background(), api, "BTC_THB", time.Unix(1699999800, 0), time.Unix(1700000100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if open := agg.Open(); len(open) != 1 || open[0].Time.Unix() != 1700000040-1700000040%420 {
+		t.Fatalf("unexpected seeded candles %v", open)
+	}
+}
+
+func TestCandleAggregatorInvalidInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a zero interval")
+		}
+	}()
+	bitkub.NewCandleAggregator(0)
+}
+
+func TestCandleAggregatorSeedFromHistory(t *testing.T) {
+	start := time.Unix(1700000100, 0)
+	now := start.Add(3 * time.Minute)
+	asOf := now.Add(30 * time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("resolution") != "1" {
+			t.Errorf("unexpected resolution %s", r.URL.Query().Get("resolution"))
+		}
+		// two 1m candles of the closed 3m candle, one of the current
+		fmt.Fprintf(w, `{"s":"ok","t":[%d,%d,%d],"o":[10,11,20],"h":[12,15,21],"l":[9,11,19],"c":[11,14,20],"v":[1,2,3]}`,
+			start.Unix(), start.Add(time.Minute).Unix(), now.Unix())
+	}))
+	defer srv.Close()
+	api := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil))
+
+	agg := bitkub.NewCandleAggregator(3 * time.Minute)
+	if err := agg.SeedFromHistory(context.Background(), api, "BTC_THB", start, asOf); err != nil {
+		t.Fatal(err)
+	}
+	if agg.AddTrade(trade(int(now.Unix()), 1, 1)) {
+		t.Fatal("expected a trade before the seed to be rejected")
+	}
+
+	var closed []string
+	agg.OnClose(func(c model.Candle) { closed = append(closed, candleString(c)) })
+	agg.AddTradeEvent(model.TradeEvent{Rate: decimal.NewFromInt(25), Amount: decimal.NewFromInt(1), Timestamp: asOf.Add(time.Second).Unix()})
+	agg.Advance(now.Add(3 * time.Minute))
+
+	want := fmt.Sprintf("%d:20/25/19/25/4", now.Unix())
+	if len(closed) != 1 || closed[0] != want {
+		t.Fatalf("got closed %v, want %s", closed, want)
+	}
+
+	if err := bitkub.NewCandleAggregator(10*time.Second).SeedFromHistory(context.Background(), api, "BTC_THB", start, asOf); err == nil {
+		t.Fatal("expected an error for an interval shorter than a minute")
+	}
+}
@@ -1,323 +1,241 @@
 package bitkub
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
+	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ChanasinP/bitkub-go/decimal"
 	"github.com/ChanasinP/bitkub-go/internal"
-	"github.com/ChanasinP/bitkub-go/internal/model"
+	"github.com/ChanasinP/bitkub-go/model"
 )
 
 const (
-	baseURL         = "https://api.bitkub.com"
-	OrderTypeLimit  = "limit"
-	OrderTypeMarket = "market"
-	OrderSideBuy    = "buy"
-	OrderSideSell   = "sell"
+	defaultBaseURL      = "https://api.bitkub.com"
+	defaultWebSocketURL = "wss://api.bitkub.com/websocket-api"
+	defaultUserAgent    = "bitkub-go/1.0"
+	defaultTimeout      = 10 * time.Second
+	OrderTypeLimit      = "limit"
+	OrderTypeMarket     = "market"
+	OrderSideBuy        = "buy"
+	OrderSideSell       = "sell"
 )
 
-type bitkubApi struct {
+// Client is a Bitkub API client. It is safe to share a Client between goroutines.
+type Client struct {
 	Timeout   time.Duration
 	ApiKey    string
 	ApiSecret string
-}
 
-func NewBitkub(key, secret string, timeout ...time.Duration) *bitkubApi {
+	baseURL   string
+	wsURL     string
+	userAgent string
+	headers   map[string]string
+	transport internal.Transport
+	retry     RetryPolicy
+	limiter   *RateLimiter
+	clock     clock
+	symbols   *SymbolRegistry
+	clientID  func() string
+	lookup    RetryPolicy
+	dryRun    bool
+	dryRunLog func(DryRunRequest)
+}
+
+// NewClient creates a Client using the given API key, secret and options.
+func NewClient(key, secret string, opts ...Option) *Client {
+	c := &Client{
+		ApiKey:    key,
+		ApiSecret: secret,
+		Timeout:   defaultTimeout,
+		baseURL:   defaultBaseURL,
+		wsURL:     defaultWebSocketURL,
+		userAgent: defaultUserAgent,
+		headers:   map[string]string{},
+		transport: &internal.FastHTTPTransport{},
+		retry:     DefaultRetryPolicy(),
+		limiter:   sharedRateLimiter(key),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NewBitkub creates a Client using the given API key and secret. The optional timeout defaults to 10 seconds.
+func NewBitkub(key, secret string, timeout ...time.Duration) *Client {
 	if len(timeout) > 0 {
-		return &bitkubApi{ApiKey: key, ApiSecret: secret, Timeout: timeout[0]}
+		return NewClient(key, secret, WithTimeout(timeout[0]))
 	}
-	return &bitkubApi{ApiKey: key, ApiSecret: secret, Timeout: time.Second * 10}
+	return NewClient(key, secret)
 }
 
-func (b *bitkubApi) sigPayload(payload map[string]interface{}) ([]byte, error) {
-	payload["ts"] = time.Now().Unix() * 1000
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return nil, err
-	}
-	h := hmac.New(sha256.New, []byte(b.ApiSecret))
-	if _, err := h.Write(body); err != nil {
-		return nil, err
+func symbolAndLimitQuery(symbol string, limit int) string {
+	params := []string{}
+	if symbol != "" {
+		params = append(params, "sym="+symbol)
 	}
-	payload["sig"] = hex.EncodeToString(h.Sum(nil))
-	body, err = json.Marshal(payload)
-	if err != nil {
-		return nil, err
+	if limit > 0 {
+		params = append(params, fmt.Sprintf("lmt=%d", limit))
 	}
-	return body, nil
+	return "?" + strings.Join(params, "&")
 }
 
-func formatFloatWithoutZeroTrail(num float64) string {
-	var (
-		zero, dot = "0", "."
-		str       = fmt.Sprintf("%f", num)
-	)
-
-	return strings.TrimRight(strings.TrimRight(str, zero), dot)
+func pagePayload(page, limit int) map[string]interface{} {
+	payload := map[string]interface{}{}
+	if page > 0 {
+		payload["p"] = page
+	}
+	if limit > 0 {
+		payload["lmt"] = limit
+	}
+	return payload
 }
 
 // GetServerStatus Get endpoint status. When status is not ok, it is highly recommended to wait until the status changes back to ok.
-func (b *bitkubApi) GetServerStatus() ([]model.ServerStatus, error) {
-	url := baseURL + "/api/status"
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
+func (b *Client) GetServerStatus() ([]model.ServerStatus, error) {
+	return b.GetServerStatusCtx(context.Background())
+}
 
+// GetServerStatusCtx is like GetServerStatus but carries ctx for cancellation and deadlines.
+func (b *Client) GetServerStatusCtx(ctx context.Context) ([]model.ServerStatus, error) {
 	ret := []model.ServerStatus{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.get(ctx, "/api/status", &ret); err != nil {
 		return nil, err
 	}
-
 	return ret, nil
 }
 
 // GetServerTime Get server timestamp.
-func (b *bitkubApi) GetServerTime() (time.Time, error) {
-	url := baseURL + "/api/servertime"
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return time.Time{}, err
-	}
-
-	if resp.StatusCode() != 200 {
-		return time.Time{}, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
+func (b *Client) GetServerTime() (time.Time, error) {
+	return b.GetServerTimeCtx(context.Background())
+}
 
-	i, err := strconv.Atoi(string(resp.Body()))
-	if err != nil {
+// GetServerTimeCtx is like GetServerTime but carries ctx for cancellation and deadlines.
+func (b *Client) GetServerTimeCtx(ctx context.Context) (time.Time, error) {
+	var ts int64
+	if err := b.get(ctx, "/api/servertime", &ts); err != nil {
 		return time.Time{}, err
 	}
-	fmt.Printf("%s=%d\n", resp.Body(), i)
 
-	return time.Time{}, nil
+	// the endpoint answers in seconds, newer versions in milliseconds
+	if ts > 1e12 {
+		return time.UnixMilli(ts), nil
+	}
+	return time.Unix(ts, 0), nil
 }
 
 // GetMarketSymbols List all available symbols.
-func (b *bitkubApi) GetMarketSymbols() ([]model.MarketSymbol, error) {
-	url := baseURL + "/api/market/symbols"
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
+func (b *Client) GetMarketSymbols() ([]model.MarketSymbol, error) {
+	return b.GetMarketSymbolsCtx(context.Background())
+}
 
+// GetMarketSymbolsCtx is like GetMarketSymbols but carries ctx for cancellation and deadlines.
+func (b *Client) GetMarketSymbolsCtx(ctx context.Context) ([]model.MarketSymbol, error) {
 	ret := model.MarketSymbolResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.get(ctx, "/api/market/symbols", &ret); err != nil {
 		return nil, err
 	}
+	return ret.Result, nil
+}
 
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
+// GetSymbolInfo List all available symbols with their precision and minimum order size.
+func (b *Client) GetSymbolInfo() ([]model.SymbolInfo, error) {
+	return b.GetSymbolInfoCtx(context.Background())
+}
 
+// GetSymbolInfoCtx is like GetSymbolInfo but carries ctx for cancellation and deadlines.
+func (b *Client) GetSymbolInfoCtx(ctx context.Context) ([]model.SymbolInfo, error) {
+	ret := model.SymbolInfoResponse{}
+	if err := b.get(ctx, "/api/v3/market/symbols", &ret); err != nil {
+		return nil, err
+	}
 	return ret.Result, nil
 }
 
 // GetMarketTickers Get ticker information.
-func (b *bitkubApi) GetMarketTickers(symbol string) (map[string]model.MarketTicker, error) {
-	url := baseURL + "/api/market/ticker"
+func (b *Client) GetMarketTickers(symbol string) (map[string]model.MarketTicker, error) {
+	return b.GetMarketTickersCtx(context.Background(), symbol)
+}
+
+// GetMarketTickersCtx is like GetMarketTickers but carries ctx for cancellation and deadlines.
+func (b *Client) GetMarketTickersCtx(ctx context.Context, symbol string) (map[string]model.MarketTicker, error) {
+	path := "/api/market/ticker"
 	if symbol != "" {
-		url += "?sym=" + symbol
-	}
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
+		path += "?sym=" + symbol
 	}
 
 	ret := map[string]model.MarketTicker{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.get(ctx, path, &ret); err != nil {
 		return nil, err
 	}
-
 	return ret, nil
 }
 
 // GetMarketTrades List recent trades.
-func (b *bitkubApi) GetMarketTrades(symbol string, limit int) ([]model.MarketTrade, error) {
-	url := baseURL + "/api/market/trades"
-	params := []string{}
-	if symbol != "" {
-		params = append(params, "sym="+symbol)
-	}
-	if limit > 0 {
-		params = append(params, fmt.Sprintf("lmt=%d", limit))
-	}
-	url += "?" + strings.Join(params, "&")
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
-	ret := model.DefaultResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
+func (b *Client) GetMarketTrades(symbol string, limit int) ([]model.MarketTrade, error) {
+	return b.GetMarketTradesCtx(context.Background(), symbol, limit)
+}
 
-	trades := []model.MarketTrade{}
-	for _, item := range ret.Result.([]interface{}) {
-		t := item.([]interface{})
-		trades = append(trades, model.MarketTrade{
-			Timestamp: int(t[0].(float64)),
-			Rate:      t[1].(float64),
-			Amount:    t[2].(float64),
-			Side:      t[3].(string),
-		})
+// GetMarketTradesCtx is like GetMarketTrades but carries ctx for cancellation and deadlines.
+func (b *Client) GetMarketTradesCtx(ctx context.Context, symbol string, limit int) ([]model.MarketTrade, error) {
+	ret := model.MarketTradesResponse{}
+	if err := b.get(ctx, "/api/market/trades"+symbolAndLimitQuery(symbol, limit), &ret); err != nil {
+		return nil, err
 	}
-
-	return trades, nil
+	return ret.Result, nil
 }
 
 // GetMarketBids List open buy orders.
-func (b *bitkubApi) GetMarketBids(symbol string, limit int) ([]model.MarketBidAndAsk, error) {
-	url := baseURL + "/api/market/bids"
-	params := []string{}
-	if symbol != "" {
-		params = append(params, "sym="+symbol)
-	}
-	if limit > 0 {
-		params = append(params, fmt.Sprintf("lmt=%d", limit))
-	}
-	url += "?" + strings.Join(params, "&")
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
-	ret := model.DefaultResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
+func (b *Client) GetMarketBids(symbol string, limit int) ([]model.MarketBidAndAsk, error) {
+	return b.GetMarketBidsCtx(context.Background(), symbol, limit)
+}
 
-	bids := []model.MarketBidAndAsk{}
-	for _, item := range ret.Result.([]interface{}) {
-		b := item.([]interface{})
-		bids = append(bids, model.MarketBidAndAsk{
-			OrderID:   int64(b[0].(float64)),
-			Timestamp: int(b[1].(float64)),
-			Volumn:    b[2].(float64),
-			Rate:      b[3].(float64),
-			Amount:    b[4].(float64),
-		})
+// GetMarketBidsCtx is like GetMarketBids but carries ctx for cancellation and deadlines.
+func (b *Client) GetMarketBidsCtx(ctx context.Context, symbol string, limit int) ([]model.MarketBidAndAsk, error) {
+	ret := model.MarketBidAndAskResponse{}
+	if err := b.get(ctx, "/api/market/bids"+symbolAndLimitQuery(symbol, limit), &ret); err != nil {
+		return nil, err
 	}
-
-	return bids, nil
+	return ret.Result, nil
 }
 
 // GetMarketAsks List open sell orders.
-func (b *bitkubApi) GetMarketAsks(symbol string, limit int) ([]model.MarketBidAndAsk, error) {
-	url := baseURL + "/api/market/asks"
-	params := []string{}
-	if symbol != "" {
-		params = append(params, "sym="+symbol)
-	}
-	if limit > 0 {
-		params = append(params, fmt.Sprintf("lmt=%d", limit))
-	}
-	url += "?" + strings.Join(params, "&")
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
-	ret := model.DefaultResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
+func (b *Client) GetMarketAsks(symbol string, limit int) ([]model.MarketBidAndAsk, error) {
+	return b.GetMarketAsksCtx(context.Background(), symbol, limit)
+}
 
-	asks := []model.MarketBidAndAsk{}
-	for _, item := range ret.Result.([]interface{}) {
-		a := item.([]interface{})
-		asks = append(asks, model.MarketBidAndAsk{
-			OrderID:   int64(a[0].(float64)),
-			Timestamp: int(a[1].(float64)),
-			Volumn:    a[2].(float64),
-			Rate:      a[3].(float64),
-			Amount:    a[4].(float64),
-		})
+// GetMarketAsksCtx is like GetMarketAsks but carries ctx for cancellation and deadlines.
+func (b *Client) GetMarketAsksCtx(ctx context.Context, symbol string, limit int) ([]model.MarketBidAndAsk, error) {
+	ret := model.MarketBidAndAskResponse{}
+	if err := b.get(ctx, "/api/market/asks"+symbolAndLimitQuery(symbol, limit), &ret); err != nil {
+		return nil, err
 	}
-
-	return asks, nil
+	return ret.Result, nil
 }
 
 // GetMarketOrderbook List all open orders.
-func (b *bitkubApi) GetMarketBooks(symbol string, limit int) (map[string][]model.MarketBidAndAsk, error) {
-	url := baseURL + "/api/market/books"
-	params := []string{}
-	if symbol != "" {
-		params = append(params, "sym="+symbol)
-	}
-	if limit > 0 {
-		params = append(params, fmt.Sprintf("lmt=%d", limit))
-	}
-	url += "?" + strings.Join(params, "&")
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
-	ret := model.DefaultResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+func (b *Client) GetMarketBooks(symbol string, limit int) (map[string][]model.MarketBidAndAsk, error) {
+	return b.GetMarketBooksCtx(context.Background(), symbol, limit)
+}
+
+// GetMarketBooksCtx is like GetMarketBooks but carries ctx for cancellation and deadlines.
+func (b *Client) GetMarketBooksCtx(ctx context.Context, symbol string, limit int) (map[string][]model.MarketBidAndAsk, error) {
+	ret := model.MarketBooksResponse{}
+	if err := b.get(ctx, "/api/market/books"+symbolAndLimitQuery(symbol, limit), &ret); err != nil {
 		return nil, err
 	}
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	books := map[string][]model.MarketBidAndAsk{}
-	for key, item := range ret.Result.(map[string]interface{}) {
-		bidOrAsk := item.([]interface{})
-		books[key] = []model.MarketBidAndAsk{}
-		for _, b := range bidOrAsk {
-			b := b.([]interface{})
-			books[key] = append(books[key], model.MarketBidAndAsk{
-				OrderID:   int64(b[0].(float64)),
-				Timestamp: int(b[1].(float64)),
-				Volumn:    b[2].(float64),
-				Rate:      b[3].(float64),
-				Amount:    b[4].(float64),
-			})
-		}
-	}
+	return ret.Result, nil
+}
 
-	return books, nil
+// GetTradingViewHistory Get historical data for TradingView chart. See GetCandles for typed candles.
+func (b *Client) GetTradingViewHistory(symbol, resolution string, from, to int) (map[string]interface{}, error) {
+	return b.GetTradingViewHistoryCtx(context.Background(), symbol, resolution, from, to)
 }
 
-// GetTradingViewHistory Get historical data for TradingView chart.
-func (b *bitkubApi) GetTradingViewHistory(symbol, resolution string, from, to int) (map[string]interface{}, error) {
-	url := baseURL + "/tradingview/history"
+// GetTradingViewHistoryCtx is like GetTradingViewHistory but carries ctx for cancellation and deadlines.
+func (b *Client) GetTradingViewHistoryCtx(ctx context.Context, symbol, resolution string, from, to int) (map[string]interface{}, error) {
 	params := []string{}
 	if symbol != "" {
 		params = append(params, "sym="+symbol)
@@ -331,1185 +249,452 @@ func (b *bitkubApi) GetTradingViewHistory(symbol, resolution string, from, to in
 	if to > 0 {
 		params = append(params, fmt.Sprintf("to=%d", to))
 	}
-	url += "?" + strings.Join(params, "&")
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
+
 	ret := map[string]interface{}{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.get(ctx, "/tradingview/history?"+strings.Join(params, "&"), &ret); err != nil {
 		return nil, err
 	}
 	return ret, nil
 }
 
 // GetMarketDepth Get depth information.
-func (b *bitkubApi) GetMarketDepth(symbol string, limit int) (map[string][]model.MarketDepth, error) {
-	url := baseURL + "/api/market/depth"
-	params := []string{}
-	if symbol != "" {
-		params = append(params, "sym="+symbol)
-	}
-	if limit > 0 {
-		params = append(params, fmt.Sprintf("lmt=%d", limit))
-	}
-	url += "?" + strings.Join(params, "&")
-	resp, err := internal.Get(url, map[string]string{}, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("response status code is %d", resp.StatusCode())
-	}
-	ret := map[string]interface{}{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
+func (b *Client) GetMarketDepth(symbol string, limit int) (map[string][]model.MarketDepth, error) {
+	return b.GetMarketDepthCtx(context.Background(), symbol, limit)
+}
 
-	depth := map[string][]model.MarketDepth{}
-	for key, itemList := range ret {
-		depth[key] = []model.MarketDepth{}
-		for _, item := range itemList.([]interface{}) {
-			item := item.([]interface{})
-			depth[key] = append(depth[key], model.MarketDepth{
-				Price:  item[0].(float64),
-				Volumn: item[1].(float64),
-			})
-		}
+// GetMarketDepthCtx is like GetMarketDepth but carries ctx for cancellation and deadlines.
+func (b *Client) GetMarketDepthCtx(ctx context.Context, symbol string, limit int) (map[string][]model.MarketDepth, error) {
+	ret := map[string][]model.MarketDepth{}
+	if err := b.get(ctx, "/api/market/depth"+symbolAndLimitQuery(symbol, limit), &ret); err != nil {
+		return nil, err
 	}
-	return depth, nil
+	return ret, nil
 }
 
 // GetWallet Get user available balances (for both available and reserved balances please use GetBalances)
-func (b *bitkubApi) GetWallet() (map[string]interface{}, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
+func (b *Client) GetWallet() (model.Wallet, error) {
+	return b.GetWalletCtx(context.Background())
+}
 
-	url := baseURL + "/api/market/wallet"
-	payload, err := b.sigPayload(map[string]interface{}{})
-	if err != nil {
+// GetWalletCtx is like GetWallet but carries ctx for cancellation and deadlines.
+func (b *Client) GetWalletCtx(ctx context.Context) (model.Wallet, error) {
+	ret := model.WalletResponse{}
+	if err := b.post(ctx, "/api/market/wallet", map[string]interface{}{}, &ret); err != nil {
 		return nil, err
 	}
+	return ret.Result, nil
+}
 
-	resp, err := internal.Post(url, map[string]string{"X-BTK-APIKEY": b.ApiKey}, payload, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+// GetBalances Get balances info: this includes both available and reserved balances.
+func (b *Client) GetBalances() (map[string]model.Balance, error) {
+	return b.GetBalancesCtx(context.Background())
+}
 
-	ret := model.DefaultResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+// GetBalancesCtx is like GetBalances but carries ctx for cancellation and deadlines.
+func (b *Client) GetBalancesCtx(ctx context.Context) (map[string]model.Balance, error) {
+	ret := model.BalancesResponse{}
+	if err := b.post(ctx, "/api/market/balances", map[string]interface{}{}, &ret); err != nil {
 		return nil, err
 	}
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
+	return ret.Result, nil
+}
 
-	return ret.Result.(map[string]interface{}), nil
+// PlaceBid Create a buy order.
+func (b *Client) PlaceBid(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceBidCtx(context.Background(), symbol, bitType, amount, rate, clientID...)
 }
 
-// GetBalances Get balances info: this includes both available and reserved balances.
-func (b *bitkubApi) GetBalances() (map[string]model.Balance, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
+// PlaceBidCtx is like PlaceBid but carries ctx for cancellation and deadlines.
+func (b *Client) PlaceBidCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceOrderCtx(ctx, newOrderRequest(OrderSideBuy, symbol, bitType, amount, rate, clientID))
+}
 
-	url := baseURL + "/api/market/balances"
-	payload, err := b.sigPayload(map[string]interface{}{})
-	if err != nil {
-		return nil, err
-	}
+// PlaceBidTest Test creating a buy order (no balance is deducted).
+func (b *Client) PlaceBidTest(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceBidTestCtx(context.Background(), symbol, bitType, amount, rate, clientID...)
+}
 
-	resp, err := internal.Post(url, map[string]string{"X-BTK-APIKEY": b.ApiKey}, payload, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+// PlaceBidTestCtx is like PlaceBidTest but carries ctx for cancellation and deadlines.
+func (b *Client) PlaceBidTestCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceOrderCtx(ctx, newOrderRequest(OrderSideBuy, symbol, bitType, amount, rate, clientID).Test())
+}
 
-	ret := model.DefaultResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
+// PlaceAsk Create a sell order.
+func (b *Client) PlaceAsk(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceAskCtx(context.Background(), symbol, bitType, amount, rate, clientID...)
+}
 
-	balances := map[string]model.Balance{}
-	for key, item := range ret.Result.(map[string]interface{}) {
-		balances[key] = model.Balance{
-			Available: item.(map[string]interface{})["available"].(float64),
-			Reserved:  item.(map[string]interface{})["reserved"].(float64),
-		}
-	}
-	return balances, nil
+// PlaceAskCtx is like PlaceAsk but carries ctx for cancellation and deadlines.
+func (b *Client) PlaceAskCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceOrderCtx(ctx, newOrderRequest(OrderSideSell, symbol, bitType, amount, rate, clientID))
 }
 
-// PlaceBid Create a buy order.
-func (b *bitkubApi) PlaceBid(symbol, bitType string, amount, rate float64, clientID ...string) (*model.Order, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
+// PlaceAskTest Test creating a sell order (no balance is deducted).
+func (b *Client) PlaceAskTest(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceAskTestCtx(context.Background(), symbol, bitType, amount, rate, clientID...)
+}
+
+// PlaceAskTestCtx is like PlaceAskTest but carries ctx for cancellation and deadlines.
+func (b *Client) PlaceAskTestCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceOrderCtx(ctx, newOrderRequest(OrderSideSell, symbol, bitType, amount, rate, clientID).Test())
+}
+
+// PlaceAskByFiat Create a sell order by specifying the fiat amount you want to receive (selling amount of cryptocurrency is automatically calculated). If order type is market, currrent highest bid will be used as rate.
+func (b *Client) PlaceAskByFiat(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return b.PlaceAskByFiatCtx(context.Background(), symbol, bitType, amount, rate, clientID...)
+}
+
+// PlaceAskByFiatCtx is like PlaceAskByFiat but carries ctx for cancellation and deadlines.
+func (b *Client) PlaceAskByFiatCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	req := newOrderRequest(OrderSideSell, symbol, bitType, amount, rate, clientID)
+	req.byQuote = true
+	return b.PlaceOrderCtx(ctx, req)
+}
+
+func orderRefPayload(symbol, side, hash string, id int) (map[string]interface{}, error) {
+	if hash != "" {
+		return map[string]interface{}{"hash": hash}, nil
 	}
 	if symbol == "" {
 		return nil, fmt.Errorf("symbol is empty")
 	}
-	if bitType == "" {
-		return nil, fmt.Errorf("bit type is empty")
+	if side == "" {
+		return nil, fmt.Errorf("side is empty")
 	}
-	if bitType != OrderTypeLimit && bitType != OrderTypeMarket {
-		return nil, fmt.Errorf("bit type is invalid")
+	if id == 0 {
+		return nil, fmt.Errorf("id is empty")
 	}
-	if bitType == OrderTypeMarket {
-		rate = 0
+	if side != OrderSideBuy && side != OrderSideSell {
+		return nil, fmt.Errorf("side is invalid")
 	}
+	return map[string]interface{}{
+		"sym": symbol,
+		"id":  fmt.Sprintf("%d", id),
+		"sd":  side,
+	}, nil
+}
 
-	url := baseURL + "/api/market/place-bid"
+// CancelOrder Cancel an open order.
+func (b *Client) CancelOrder(symbol, side, hash string, id int) error {
+	return b.CancelOrderCtx(context.Background(), symbol, side, hash, id)
+}
 
-	payload := map[string]interface{}{
-		"sym": symbol,
-		"typ": bitType,
-		"amt": formatFloatWithoutZeroTrail(amount),
-		"rat": formatFloatWithoutZeroTrail(rate),
-	}
-	if len(clientID) > 0 {
-		payload["client_id"] = clientID[0]
-	}
-	payloadBytes, err := b.sigPayload(payload)
+// CancelOrderCtx is like CancelOrder but carries ctx for cancellation and deadlines.
+func (b *Client) CancelOrderCtx(ctx context.Context, symbol, side, hash string, id int) error {
+	payload, err := orderRefPayload(symbol, side, hash, id)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if hash == "" {
+		if _, payload["sym"], err = b.checkSymbol(ctx, symbol, false); err != nil {
+			return err
+		}
 	}
+	return b.post(ctx, "/api/market/cancel-order", payload, nil)
+}
+
+// GetOpenOrder List all open orders of the given symbol.
+func (b *Client) GetOpenOrder(symbol string) ([]model.OpenOrder, error) {
+	return b.GetOpenOrderCtx(context.Background(), symbol)
+}
 
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
+// GetOpenOrderCtx is like GetOpenOrder but carries ctx for cancellation and deadlines.
+func (b *Client) GetOpenOrderCtx(ctx context.Context, symbol string) ([]model.OpenOrder, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is empty")
+	}
+	_, symbol, err := b.checkSymbol(ctx, symbol, false)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
 
-	ret := model.OrderResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	ret := model.OpenOrderResponse{}
+	if err := b.post(ctx, "/api/market/my-open-orders", map[string]interface{}{"sym": symbol}, &ret); err != nil {
 		return nil, err
 	}
+	return ret.Result, nil
+}
 
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return &ret.Result, nil
+// GetOrderHistory List all orders that have already matched.
+func (b *Client) GetOrderHistory(symbol string, page, limit int, start, end int64) ([]model.OrderHistory, *model.OrderHistoryPagination, error) {
+	return b.GetOrderHistoryCtx(context.Background(), symbol, page, limit, start, end)
 }
 
-// PlaceBidTest Test creating a buy order (no balance is deducted).
-func (b *bitkubApi) PlaceBidTest(symbol, bitType string, amount, rate float64, clientID ...string) (*model.Order, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
+// GetOrderHistoryCtx is like GetOrderHistory but carries ctx for cancellation and deadlines.
+func (b *Client) GetOrderHistoryCtx(ctx context.Context, symbol string, page, limit int, start, end int64) ([]model.OrderHistory, *model.OrderHistoryPagination, error) {
 	if symbol == "" {
-		return nil, fmt.Errorf("symbol is empty")
-	}
-	if bitType == "" {
-		return nil, fmt.Errorf("bit type is empty")
-	}
-	if bitType != OrderTypeLimit && bitType != OrderTypeMarket {
-		return nil, fmt.Errorf("bit type is invalid")
+		return nil, nil, fmt.Errorf("symbol is empty")
 	}
-	if bitType == OrderTypeMarket {
-		rate = 0
+	_, symbol, err := b.checkSymbol(ctx, symbol, false)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	url := baseURL + "/api/market/place-bid/test"
-	payload := map[string]interface{}{
-		"sym": symbol,
-		"typ": bitType,
-		"amt": formatFloatWithoutZeroTrail(amount),
-		"rat": formatFloatWithoutZeroTrail(rate),
+	payload := pagePayload(page, limit)
+	payload["sym"] = symbol
+	if start > 0 {
+		payload["start"] = start
 	}
-	if len(clientID) > 0 {
-		payload["client_id"] = clientID[0]
+	if end > 0 {
+		payload["end"] = end
 	}
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
+
+	ret := model.OrderHistoryResponse{}
+	if err := b.post(ctx, "/api/market/my-order-history", payload, &ret); err != nil {
+		return nil, nil, err
 	}
+	return ret.Result, &ret.Pagination, nil
+}
+
+// GetOrderInfo Get information regarding the specified order.
+func (b *Client) GetOrderInfo(symbol, side, hash string, id int) (*model.OrderInfo, error) {
+	return b.GetOrderInfoCtx(context.Background(), symbol, side, hash, id)
+}
 
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
+// GetOrderInfoCtx is like GetOrderInfo but carries ctx for cancellation and deadlines.
+func (b *Client) GetOrderInfoCtx(ctx context.Context, symbol, side, hash string, id int) (*model.OrderInfo, error) {
+	payload, err := orderRefPayload(symbol, side, hash, id)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
+	if hash == "" {
+		if _, payload["sym"], err = b.checkSymbol(ctx, symbol, false); err != nil {
+			return nil, err
+		}
 	}
 
-	ret := model.OrderResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	ret := model.OrderInfoResponse{}
+	if err := b.post(ctx, "/api/market/order-info", payload, &ret); err != nil {
 		return nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return &ret.Result, nil
-}
-
-// PlaceAsk Create a sell order.
-func (b *bitkubApi) PlaceAsk(symbol, bitType string, amount, rate float64, clientID ...string) (*model.Order, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
-	if symbol == "" {
-		return nil, fmt.Errorf("symbol is empty")
-	}
-	if bitType == "" {
-		return nil, fmt.Errorf("bit type is empty")
-	}
-	if bitType != OrderTypeLimit && bitType != OrderTypeMarket {
-		return nil, fmt.Errorf("bit type is invalid")
-	}
-	if bitType == OrderTypeMarket {
-		rate = 0
-	}
-
-	url := baseURL + "/api/market/place-ask"
-	payload := map[string]interface{}{
-		"sym": symbol,
-		"typ": bitType,
-		"amt": formatFloatWithoutZeroTrail(amount),
-		"rat": formatFloatWithoutZeroTrail(rate),
-	}
-	if len(clientID) > 0 {
-		payload["client_id"] = clientID[0]
-	}
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
-	ret := model.OrderResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return &ret.Result, nil
-}
-
-// PlaceAskTest Test creating a sell order (no balance is deducted).
-func (b *bitkubApi) PlaceAskTest(symbol, bitType string, amount, rate float64, clientID ...string) (*model.Order, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
-	if symbol == "" {
-		return nil, fmt.Errorf("symbol is empty")
-	}
-	if bitType == "" {
-		return nil, fmt.Errorf("bit type is empty")
-	}
-	if bitType != OrderTypeLimit && bitType != OrderTypeMarket {
-		return nil, fmt.Errorf("bit type is invalid")
-	}
-	if bitType == OrderTypeMarket {
-		rate = 0
-	}
-
-	url := baseURL + "/api/market/place-ask/test"
-	payload := map[string]interface{}{
-		"sym": symbol,
-		"typ": bitType,
-		"amt": formatFloatWithoutZeroTrail(amount),
-		"rat": formatFloatWithoutZeroTrail(rate),
-	}
-	if len(clientID) > 0 {
-		payload["client_id"] = clientID[0]
-	}
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
-	ret := model.OrderResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return &ret.Result, nil
-}
-
-// PlaceAskByFiat Create a sell order by specifying the fiat amount you want to receive (selling amount of cryptocurrency is automatically calculated). If order type is market, currrent highest bid will be used as rate.
-func (b *bitkubApi) PlaceAskByFiat(symbol, bitType string, amount, rate float64) (*model.Order, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
-	if symbol == "" {
-		return nil, fmt.Errorf("symbol is empty")
-	}
-	if bitType == "" {
-		return nil, fmt.Errorf("bit type is empty")
-	}
-	if bitType != OrderTypeLimit && bitType != OrderTypeMarket {
-		return nil, fmt.Errorf("bit type is invalid")
-	}
-	if bitType == OrderTypeMarket {
-		rate = 0
-	}
-
-	url := baseURL + "/api/market/place-ask-by-fiat"
-	payload := map[string]interface{}{
-		"sym": symbol,
-		"typ": bitType,
-		"amt": formatFloatWithoutZeroTrail(amount),
-		"rat": formatFloatWithoutZeroTrail(rate),
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
-	ret := model.OrderResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return &ret.Result, nil
-}
-
-// CancelOrder Cancel an open order.
-func (b *bitkubApi) CancelOrder(symbol, side, hash string, id int) error {
-	if b.ApiKey == "" {
-		return fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return fmt.Errorf("api secret is empty")
-	}
-	var payload map[string]interface{}
-	if hash == "" {
-		if symbol == "" {
-			return fmt.Errorf("symbol is empty")
-		}
-		if side == "" {
-			return fmt.Errorf("side is empty")
-		}
-		if id == 0 {
-			return fmt.Errorf("id is empty")
-		}
-		if side != OrderSideBuy && side != OrderSideSell {
-			return fmt.Errorf("side is invalid")
-		}
-		payload = map[string]interface{}{
-			"sym": symbol,
-			"id":  fmt.Sprintf("%d", id),
-			"sd":  side,
-		}
-	} else {
-		payload = map[string]interface{}{"hash": hash}
-	}
-
-	url := baseURL + "/api/market/cancel-order"
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return err
-	}
-
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
-	ret := model.ErrorResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return err
-	}
-
-	if ret.Error != 0 {
-		return fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return nil
-}
-
-// GetOpenOrder List all open orders of the given symbol.
-func (b *bitkubApi) GetOpenOrder(symbol string) ([]model.OpenOrder, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
-	if symbol == "" {
-		return nil, fmt.Errorf("symbol is empty")
-	}
-
-	url := baseURL + "/api/market/my-open-orders"
-	payload := map[string]interface{}{"sym": symbol}
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
-	ret := model.OpenOrderResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return ret.Result, nil
-}
-
-// GetOrderHistory List all orders that have already matched.
-func (b *bitkubApi) GetOrderHistory(symbol string, page, limit int, start, end int64) ([]model.OrderHistory, *model.OrderHistoryPagination, error) {
-	if b.ApiKey == "" {
-		return nil, nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, nil, fmt.Errorf("api secret is empty")
-	}
-	if symbol == "" {
-		return nil, nil, fmt.Errorf("symbol is empty")
-	}
-
-	url := baseURL + "/api/market/my-order-history"
-	payload := map[string]interface{}{"sym": symbol}
-	if page > 0 {
-		payload["p"] = page
-	}
-	if limit > 0 {
-		payload["lmt"] = limit
-	}
-	if start > 0 {
-		payload["start"] = start
-	}
-	if end > 0 {
-		payload["end"] = end
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
-	ret := model.OrderHistoryResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, nil, err
-	}
-
-	if ret.Error != 0 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return ret.Result, &ret.Pagination, nil
-}
-
-// GetOrderInfo Get information regarding the specified order.
-func (b *bitkubApi) GetOrderInfo(symbol, side, hash string, id int) (*model.OrderInfo, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
-	var payload map[string]interface{}
-	if hash == "" {
-		if symbol == "" {
-			return nil, fmt.Errorf("symbol is empty")
-		}
-		if side == "" {
-			return nil, fmt.Errorf("side is empty")
-		}
-		if id == 0 {
-			return nil, fmt.Errorf("id is empty")
-		}
-		if side != OrderSideBuy && side != OrderSideSell {
-			return nil, fmt.Errorf("side is invalid")
-		}
-		payload = map[string]interface{}{
-			"sym": symbol,
-			"id":  fmt.Sprintf("%d", id),
-			"sd":  side,
-		}
-	} else {
-		payload = map[string]interface{}{"hash": hash}
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	url := baseURL + "/api/market/order-info"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
-	ret := model.OrderInfoResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return nil, err
-	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return &ret.Result, nil
 }
 
 // GetCryptoAddresses List all crypto addresses.
-func (b *bitkubApi) GetCryptoAddresses(page, limit int) ([]model.CryptoAddress, *model.Pagination, error) {
-	if b.ApiKey == "" {
-		return nil, nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, nil, fmt.Errorf("api secret is empty")
-	}
-
-	payload := map[string]interface{}{}
-	if page > 0 {
-		payload["p"] = page
-	}
-	if limit > 0 {
-		payload["lmt"] = limit
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	url := baseURL + "/api/crypto/addresses"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+func (b *Client) GetCryptoAddresses(page, limit int) ([]model.CryptoAddress, *model.Pagination, error) {
+	return b.GetCryptoAddressesCtx(context.Background(), page, limit)
+}
 
+// GetCryptoAddressesCtx is like GetCryptoAddresses but carries ctx for cancellation and deadlines.
+func (b *Client) GetCryptoAddressesCtx(ctx context.Context, page, limit int) ([]model.CryptoAddress, *model.Pagination, error) {
 	ret := model.CryptoAddressResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/crypto/addresses", pagePayload(page, limit), &ret); err != nil {
 		return nil, nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return ret.Result, &ret.Pagination, nil
 }
 
-// CryptoWithdraw Make a withdrawal to a trusted address.
-func (b *bitkubApi) CryptoWithdraw(currency, address string, amount float64, memo string) (*model.CryptoWithdraw, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
+func withdrawPayload(currency, address string, amount decimal.Decimal, memo string) (map[string]interface{}, error) {
 	if currency == "" {
 		return nil, fmt.Errorf("currency is empty")
 	}
 	if address == "" {
 		return nil, fmt.Errorf("address is empty")
 	}
-	if amount <= 0 {
+	if !amount.IsPositive() {
 		return nil, fmt.Errorf("amount is invalid")
 	}
 
 	payload := map[string]interface{}{
 		"cur": currency,
 		"adr": address,
-		"amt": fmt.Sprintf("%f", amount),
+		"amt": amount.String(),
 	}
 	if memo != "" {
 		payload["mem"] = memo
 	}
+	return payload, nil
+}
 
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
+// CryptoWithdraw Make a withdrawal to a trusted address.
+func (b *Client) CryptoWithdraw(currency, address string, amount decimal.Decimal, memo string) (*model.CryptoWithdraw, error) {
+	return b.CryptoWithdrawCtx(context.Background(), currency, address, amount, memo)
+}
 
-	url := baseURL + "/api/crypto/withdraw"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
+// CryptoWithdrawCtx is like CryptoWithdraw but carries ctx for cancellation and deadlines.
+func (b *Client) CryptoWithdrawCtx(ctx context.Context, currency, address string, amount decimal.Decimal, memo string) (*model.CryptoWithdraw, error) {
+	payload, err := withdrawPayload(currency, address, amount, memo)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
 
 	ret := model.CryptoWithdrawResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/crypto/withdraw", payload, &ret); err != nil {
 		return nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return &ret.Result, nil
 }
 
 // CryptoInternalWithdraw Make a withdraw to an internal address. The destination address is not required to be a trusted address. This API is not enabled by default, Only KYB users can request this feature by contacting us via support@bitkub.com
-func (b *bitkubApi) CryptoInternalWithdraw(currency, address string, amount float64, memo string) (*model.CryptoWithdraw, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
-	if currency == "" {
-		return nil, fmt.Errorf("currency is empty")
-	}
-	if address == "" {
-		return nil, fmt.Errorf("address is empty")
-	}
-	if amount <= 0 {
-		return nil, fmt.Errorf("amount is invalid")
-	}
-
-	payload := map[string]interface{}{
-		"cur": currency,
-		"adr": address,
-		"amt": fmt.Sprintf("%f", amount),
-	}
-	if memo != "" {
-		payload["mem"] = memo
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
+func (b *Client) CryptoInternalWithdraw(currency, address string, amount decimal.Decimal, memo string) (*model.CryptoWithdraw, error) {
+	return b.CryptoInternalWithdrawCtx(context.Background(), currency, address, amount, memo)
+}
 
-	url := baseURL + "/api/crypto/internal-withdraw"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
+// CryptoInternalWithdrawCtx is like CryptoInternalWithdraw but carries ctx for cancellation and deadlines.
+func (b *Client) CryptoInternalWithdrawCtx(ctx context.Context, currency, address string, amount decimal.Decimal, memo string) (*model.CryptoWithdraw, error) {
+	payload, err := withdrawPayload(currency, address, amount, memo)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
 
 	ret := model.CryptoWithdrawResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/crypto/internal-withdraw", payload, &ret); err != nil {
 		return nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return &ret.Result, nil
 }
 
 // GetCryptoDepositHistory List crypto deposit history.
-func (b *bitkubApi) GetCryptoDepositHistory(page, limit int) ([]model.CryptoDeposit, *model.Pagination, error) {
-	if b.ApiKey == "" {
-		return nil, nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, nil, fmt.Errorf("api secret is empty")
-	}
-
-	payload := map[string]interface{}{}
-	if page > 0 {
-		payload["p"] = page
-	}
-	if limit > 0 {
-		payload["lmt"] = limit
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	url := baseURL + "/api/crypto/deposit-history"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+func (b *Client) GetCryptoDepositHistory(page, limit int) ([]model.CryptoDeposit, *model.Pagination, error) {
+	return b.GetCryptoDepositHistoryCtx(context.Background(), page, limit)
+}
 
+// GetCryptoDepositHistoryCtx is like GetCryptoDepositHistory but carries ctx for cancellation and deadlines.
+func (b *Client) GetCryptoDepositHistoryCtx(ctx context.Context, page, limit int) ([]model.CryptoDeposit, *model.Pagination, error) {
 	ret := model.CryptoDepositResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/crypto/deposit-history", pagePayload(page, limit), &ret); err != nil {
 		return nil, nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return ret.Result, &ret.Pagination, nil
 }
 
 // GetCryptoWithdrawHistory List crypto withdrawal history.
-func (b *bitkubApi) GetCryptoWithdrawHistory(page, limit int) ([]model.CryptoWithdraw, *model.Pagination, error) {
-	if b.ApiKey == "" {
-		return nil, nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, nil, fmt.Errorf("api secret is empty")
-	}
-
-	payload := map[string]interface{}{}
-	if page > 0 {
-		payload["p"] = page
-	}
-	if limit > 0 {
-		payload["lmt"] = limit
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	url := baseURL + "/api/crypto/withdraw-history"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+func (b *Client) GetCryptoWithdrawHistory(page, limit int) ([]model.CryptoWithdraw, *model.Pagination, error) {
+	return b.GetCryptoWithdrawHistoryCtx(context.Background(), page, limit)
+}
 
+// GetCryptoWithdrawHistoryCtx is like GetCryptoWithdrawHistory but carries ctx for cancellation and deadlines.
+func (b *Client) GetCryptoWithdrawHistoryCtx(ctx context.Context, page, limit int) ([]model.CryptoWithdraw, *model.Pagination, error) {
 	ret := model.CryptoWithdrawHistoryResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/crypto/withdraw-history", pagePayload(page, limit), &ret); err != nil {
 		return nil, nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return ret.Result, &ret.Pagination, nil
 }
 
 // CryptoGenerateAddress Generate a new crypto address (will replace existing address; previous address can still be used to received funds)
-func (b *bitkubApi) CryptoGenerateAddress(symbol string) ([]model.CryptoGenerateAddress, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
+func (b *Client) CryptoGenerateAddress(symbol string) ([]model.CryptoGenerateAddress, error) {
+	return b.CryptoGenerateAddressCtx(context.Background(), symbol)
+}
+
+// CryptoGenerateAddressCtx is like CryptoGenerateAddress but carries ctx for cancellation and deadlines.
+func (b *Client) CryptoGenerateAddressCtx(ctx context.Context, symbol string) ([]model.CryptoGenerateAddress, error) {
 	if symbol == "" {
 		return nil, fmt.Errorf("symbol is empty")
 	}
 
-	payload := map[string]interface{}{"sym": symbol}
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	url := baseURL + "/api/crypto/generate-address"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
 	ret := model.CryptoGenerateAddressResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/crypto/generate-address", map[string]interface{}{"sym": symbol}, &ret); err != nil {
 		return nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return ret.Result, nil
 }
 
 // GetBankAccounts List all approved bank accounts.
-func (b *bitkubApi) GetBankAccounts(page, limit int) ([]model.BankAccount, *model.Pagination, error) {
-	if b.ApiKey == "" {
-		return nil, nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, nil, fmt.Errorf("api secret is empty")
-	}
-
-	payload := map[string]interface{}{}
-	if page > 0 {
-		payload["p"] = page
-	}
-	if limit > 0 {
-		payload["lmt"] = limit
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	url := baseURL + "/api/fiat/accounts"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+func (b *Client) GetBankAccounts(page, limit int) ([]model.BankAccount, *model.Pagination, error) {
+	return b.GetBankAccountsCtx(context.Background(), page, limit)
+}
 
+// GetBankAccountsCtx is like GetBankAccounts but carries ctx for cancellation and deadlines.
+func (b *Client) GetBankAccountsCtx(ctx context.Context, page, limit int) ([]model.BankAccount, *model.Pagination, error) {
 	ret := model.FiatAccountsResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/fiat/accounts", pagePayload(page, limit), &ret); err != nil {
 		return nil, nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return ret.Result, &ret.Pagination, nil
 }
 
 // FiatWithdraw Make a withdrawal to an approved bank account.
-func (b *bitkubApi) FiatWithdraw(bankID string, amount float64) (*model.FiatWithdraw, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
+func (b *Client) FiatWithdraw(bankID string, amount decimal.Decimal) (*model.FiatWithdraw, error) {
+	return b.FiatWithdrawCtx(context.Background(), bankID, amount)
+}
+
+// FiatWithdrawCtx is like FiatWithdraw but carries ctx for cancellation and deadlines.
+func (b *Client) FiatWithdrawCtx(ctx context.Context, bankID string, amount decimal.Decimal) (*model.FiatWithdraw, error) {
 	if bankID == "" {
 		return nil, fmt.Errorf("bank id is empty")
 	}
-	if amount <= 0 {
+	if !amount.IsPositive() {
 		return nil, fmt.Errorf("amount is invalid")
 	}
 
-	payload := map[string]interface{}{"id": bankID, "amount": amount}
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	url := baseURL + "/api/fiat/withdraw"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
 	ret := model.FiatWithdrawResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/fiat/withdraw", map[string]interface{}{"id": bankID, "amount": amount.String()}, &ret); err != nil {
 		return nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return &ret.Result, nil
 }
 
 // GetFiatDepositHistory List fiat deposit history.
-func (b *bitkubApi) GetFiatDepositHistory(page, limit int) ([]model.FiatDeposit, *model.Pagination, error) {
-	if b.ApiKey == "" {
-		return nil, nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, nil, fmt.Errorf("api secret is empty")
-	}
-
-	payload := map[string]interface{}{}
-	if page > 0 {
-		payload["p"] = page
-	}
-	if limit > 0 {
-		payload["lmt"] = limit
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	url := baseURL + "/api/fiat/deposit-history"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+func (b *Client) GetFiatDepositHistory(page, limit int) ([]model.FiatDeposit, *model.Pagination, error) {
+	return b.GetFiatDepositHistoryCtx(context.Background(), page, limit)
+}
 
+// GetFiatDepositHistoryCtx is like GetFiatDepositHistory but carries ctx for cancellation and deadlines.
+func (b *Client) GetFiatDepositHistoryCtx(ctx context.Context, page, limit int) ([]model.FiatDeposit, *model.Pagination, error) {
 	ret := model.FiatDepositResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/fiat/deposit-history", pagePayload(page, limit), &ret); err != nil {
 		return nil, nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return ret.Result, &ret.Pagination, nil
 }
 
 // GetFiatWithdrawHistory List fiat withdrawal history.
-func (b *bitkubApi) GetFiatWithdrawHistory(page, limit int) ([]model.FiatWithdraw, *model.Pagination, error) {
-	if b.ApiKey == "" {
-		return nil, nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, nil, fmt.Errorf("api secret is empty")
-	}
-
-	payload := map[string]interface{}{}
-	if page > 0 {
-		payload["p"] = page
-	}
-	if limit > 0 {
-		payload["lmt"] = limit
-	}
-
-	payloadBytes, err := b.sigPayload(payload)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	url := baseURL + "/api/fiat/withdraw-history"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+func (b *Client) GetFiatWithdrawHistory(page, limit int) ([]model.FiatWithdraw, *model.Pagination, error) {
+	return b.GetFiatWithdrawHistoryCtx(context.Background(), page, limit)
+}
 
+// GetFiatWithdrawHistoryCtx is like GetFiatWithdrawHistory but carries ctx for cancellation and deadlines.
+func (b *Client) GetFiatWithdrawHistoryCtx(ctx context.Context, page, limit int) ([]model.FiatWithdraw, *model.Pagination, error) {
 	ret := model.FiatWithdrawHistoryResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/fiat/withdraw-history", pagePayload(page, limit), &ret); err != nil {
 		return nil, nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return ret.Result, &ret.Pagination, nil
 }
 
 // GetWebSocketToken Get the token for websocket authentication
-func (b *bitkubApi) GetWebSocketToken() (string, error) {
-	if b.ApiKey == "" {
-		return "", fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return "", fmt.Errorf("api secret is empty")
-	}
-
-	payloadBytes, err := b.sigPayload(map[string]interface{}{})
-	if err != nil {
-		return "", err
-	}
-
-	url := baseURL + "/api/market/wstoken"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode() != 200 {
-		return "", fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+func (b *Client) GetWebSocketToken() (string, error) {
+	return b.GetWebSocketTokenCtx(context.Background())
+}
 
-	ret := model.DefaultResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+// GetWebSocketTokenCtx is like GetWebSocketToken but carries ctx for cancellation and deadlines.
+func (b *Client) GetWebSocketTokenCtx(ctx context.Context) (string, error) {
+	ret := struct {
+		Result string `json:"result"`
+	}{}
+	if err := b.post(ctx, "/api/market/wstoken", map[string]interface{}{}, &ret); err != nil {
 		return "", err
 	}
-
-	if ret.Error != 0 {
-		return "", fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
-	return ret.Result.(string), nil
+	return ret.Result, nil
 }
 
 // GetUserLimits Check deposit/withdraw limitations and usage.
-func (b *bitkubApi) GetUserLimits() (*model.UserLimits, error) {
-	if b.ApiKey == "" {
-		return nil, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return nil, fmt.Errorf("api secret is empty")
-	}
-
-	payloadBytes, err := b.sigPayload(map[string]interface{}{})
-	if err != nil {
-		return nil, err
-	}
-
-	url := baseURL + "/api/user/limits"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
+func (b *Client) GetUserLimits() (*model.UserLimits, error) {
+	return b.GetUserLimitsCtx(context.Background())
+}
 
+// GetUserLimitsCtx is like GetUserLimits but carries ctx for cancellation and deadlines.
+func (b *Client) GetUserLimitsCtx(ctx context.Context) (*model.UserLimits, error) {
 	ret := model.UserLimitsResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+	if err := b.post(ctx, "/api/user/limits", map[string]interface{}{}, &ret); err != nil {
 		return nil, err
 	}
-
-	if ret.Error != 0 {
-		return nil, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
-	}
-
 	return &ret.Result, nil
 }
 
 // GetUserTradingCredits Check trading credit balance.
-func (b *bitkubApi) GetUserTradingCredits() (float64, error) {
-	if b.ApiKey == "" {
-		return 0, fmt.Errorf("api key is empty")
-	}
-	if b.ApiSecret == "" {
-		return 0, fmt.Errorf("api secret is empty")
-	}
-
-	payloadBytes, err := b.sigPayload(map[string]interface{}{})
-	if err != nil {
-		return 0, err
-	}
-
-	url := baseURL + "/api/user/trading-credits"
-	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
-	resp, err := internal.Post(url, headers, payloadBytes, b.Timeout)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode() != 200 {
-		return 0, fmt.Errorf("got server error (%d) : %s", resp.StatusCode(), resp.String())
-	}
-
-	ret := model.DefaultResponse{}
-	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
-		return 0, err
-	}
+func (b *Client) GetUserTradingCredits() (decimal.Decimal, error) {
+	return b.GetUserTradingCreditsCtx(context.Background())
+}
 
-	if ret.Error != 0 {
-		return 0, fmt.Errorf("got server error (%d) : %s", ret.Error, internal.GetErrorMessage(ret.Error))
+// GetUserTradingCreditsCtx is like GetUserTradingCredits but carries ctx for cancellation and deadlines.
+func (b *Client) GetUserTradingCreditsCtx(ctx context.Context) (decimal.Decimal, error) {
+	ret := struct {
+		Result decimal.Decimal `json:"result"`
+	}{}
+	if err := b.post(ctx, "/api/user/trading-credits", map[string]interface{}{}, &ret); err != nil {
+		return decimal.Zero, err
 	}
-
-	return ret.Result.(float64), nil
+	return ret.Result, nil
 }
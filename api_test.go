@@ -1,9 +1,15 @@
 package bitkub_test
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
 )
 
 const (
@@ -60,7 +66,7 @@ func TestGetMarketTrades(t *testing.T) {
 		t.Fatal(err)
 	}
 	for _, trade := range trades {
-		t.Logf("Trade timestamp %d, Rate %.03f, Amount %f, Side %s", trade.Timestamp, trade.Rate, trade.Amount, trade.Side)
+		t.Logf("Trade timestamp %d, Rate %s, Amount %s, Side %s", trade.Timestamp, trade.Rate, trade.Amount, trade.Side)
 	}
 }
 
@@ -71,7 +77,7 @@ func TestGetMarketBids(t *testing.T) {
 		t.Fatal(err)
 	}
 	for _, bid := range bids {
-		t.Logf("Bid Order ID %d, timestamp %d, Valumn %f, Rate %.03f, Amount %f", bid.OrderID, bid.Timestamp, bid.Volumn, bid.Rate, bid.Amount)
+		t.Logf("Bid Order ID %d, timestamp %d, Valumn %s, Rate %s, Amount %s", bid.OrderID, bid.Timestamp, bid.Volumn, bid.Rate, bid.Amount)
 	}
 }
 
@@ -82,7 +88,7 @@ func TestGetMarketAsks(t *testing.T) {
 		t.Fatal(err)
 	}
 	for _, ask := range asks {
-		t.Logf("Ask Order ID %d, timestamp %d, Valumn %f, Rate %.03f, Amount %f", ask.OrderID, ask.Timestamp, ask.Volumn, ask.Rate, ask.Amount)
+		t.Logf("Ask Order ID %d, timestamp %d, Valumn %s, Rate %s, Amount %s", ask.OrderID, ask.Timestamp, ask.Volumn, ask.Rate, ask.Amount)
 	}
 }
 
@@ -94,7 +100,7 @@ func TestGetMarketBooks(t *testing.T) {
 	}
 	for key, book := range books {
 		for _, item := range book {
-			t.Logf("%s Order ID %d, timestamp %d, Valumn %f, Rate %.03f, Amount %f", key, item.OrderID, item.Timestamp, item.Volumn, item.Rate, item.Amount)
+			t.Logf("%s Order ID %d, timestamp %d, Valumn %s, Rate %s, Amount %s", key, item.OrderID, item.Timestamp, item.Volumn, item.Rate, item.Amount)
 		}
 	}
 }
@@ -107,7 +113,7 @@ func TestGetMarketDepth(t *testing.T) {
 	}
 	for key, items := range depth {
 		for _, item := range items {
-			t.Logf("Depth %s, Price %.0f, Valumn %f", key, item.Price, item.Volumn)
+			t.Logf("Depth %s, Price %s, Valumn %s", key, item.Price, item.Volumn)
 		}
 	}
 }
@@ -119,8 +125,8 @@ func TestGetWallet(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	for sym, volumn := range wallet {
-		t.Logf("%s: %f", sym, volumn)
+	for _, sym := range wallet.NonZero().Currencies() {
+		t.Logf("%s: %s", sym, wallet[sym])
 	}
 }
 
@@ -132,13 +138,13 @@ func TestGetBalances(t *testing.T) {
 	}
 
 	for sym, balance := range wallet {
-		t.Logf("%s: Available=%f, Reserved=%f", sym, balance.Available, balance.Reserved)
+		t.Logf("%s: Available=%s, Reserved=%s", sym, balance.Available, balance.Reserved)
 	}
 }
 
 func TestPlaceBid(t *testing.T) {
 	api := bitkub.NewBitkub(API_KEY, API_SECRET)
-	bid, err := api.PlaceBidTest("THB_BTC", bitkub.OrderTypeMarket, 10.01, 0)
+	bid, err := api.PlaceBidTest("THB_BTC", bitkub.OrderTypeMarket, decimal.RequireFromString("10.01"), decimal.Zero)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -148,7 +154,7 @@ func TestPlaceBid(t *testing.T) {
 
 func TestPlaceAsk(t *testing.T) {
 	api := bitkub.NewBitkub(API_KEY, API_SECRET)
-	bid, err := api.PlaceAskTest("THB_BTC", bitkub.OrderTypeMarket, 0.001, 0)
+	bid, err := api.PlaceAskTest("THB_BTC", bitkub.OrderTypeMarket, decimal.RequireFromString("0.001"), decimal.Zero)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -156,6 +162,76 @@ func TestPlaceAsk(t *testing.T) {
 	t.Logf("Response : %+v", bid)
 }
 
+func TestPlaceBidPrecision(t *testing.T) {
+	var payload map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		w.Write([]byte(`{"error":0,"result":{"id":1,"typ":"limit","amt":1000.00000001,"rat":2150000.55,"fee":0.0000000025,"rec":0.00046511}}`))
+	}))
+	defer srv.Close()
+
+	api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL))
+	bid, err := api.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, decimal.RequireFromString("1000.00000001"), decimal.RequireFromString("2150000.55"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload["amt"] != "1000.00000001" || payload["rat"] != "2150000.55" {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+	if bid.Amount.String() != "1000.00000001" || bid.Fee.String() != "0.0000000025" || bid.Receive.String() != "0.00046511" {
+		t.Fatalf("unexpected order %+v", bid)
+	}
+}
+
+func TestWithdrawPayloads(t *testing.T) {
+	payloads := map[string]map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]interface{}{}
+		json.Unmarshal(body, &payload)
+		payloads[r.URL.Path] = payload
+		w.Write([]byte(`{"error":0,"result":{}}`))
+	}))
+	defer srv.Close()
+
+	api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil))
+	v3 := api.V3()
+	ctx := context.Background()
+	amount := decimal.RequireFromString("0.00012345")
+	if _, err := api.CryptoWithdraw("BTC", "addr", amount, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.CryptoInternalWithdraw("BTC", "addr", amount, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.FiatWithdraw("bank", amount); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v3.CryptoWithdraw(ctx, "BTC", "addr", amount, "", "BTC"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v3.CryptoInternalWithdraw(ctx, "BTC", "addr", amount, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v3.FiatWithdraw(ctx, "bank", amount); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, field := range map[string]string{
+		"/api/crypto/withdraw":             "amt",
+		"/api/crypto/internal-withdraw":    "amt",
+		"/api/fiat/withdraw":               "amount",
+		"/api/v3/crypto/withdraw":          "amt",
+		"/api/v3/crypto/internal-withdraw": "amt",
+		"/api/v3/fiat/withdraw":            "amt",
+	} {
+		if got := payloads[path][field]; got != "0.00012345" {
+			t.Errorf("%s: expected %s \"0.00012345\", got %#v", path, field, got)
+		}
+	}
+}
+
 func TestGetOrderHistory(t *testing.T) {
 	api := bitkub.NewBitkub(API_KEY, API_SECRET)
 	orders, pagination, err := api.GetOrderHistory("THB_BTC", 1, 10, 0, 0)
@@ -284,5 +360,5 @@ func TestGetUserTradingCredits(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	t.Logf("Response : %f", credits)
+	t.Logf("Response : %s", credits)
 }
@@ -0,0 +1,314 @@
+package bitkub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// BackfillCandle is a candle delivered by BackfillCandles.
+type BackfillCandle struct {
+	model.Candle
+	Filled bool // made up for a bar without trades, with the previous close and no volume
+	Gap    int  // number of missing bars right before this candle when gaps are not filled
+}
+
+// BackfillCheckpoint stores the time of the last candle delivered by BackfillCandles, by key.
+type BackfillCheckpoint interface {
+	Load(key string) (time.Time, bool, error)
+	Save(key string, last time.Time) error
+}
+
+type backfillConfig struct {
+	chunk       int
+	concurrency int
+	fillGaps    bool
+	checkpoint  BackfillCheckpoint
+}
+
+// BackfillOption configures BackfillCandles.
+type BackfillOption func(*backfillConfig)
+
+// WithBackfillChunk sets how many bars are requested at once. It defaults to 1000.
+func WithBackfillChunk(bars int) BackfillOption {
+	return func(c *backfillConfig) {
+		c.chunk = bars
+	}
+}
+
+// WithBackfillConcurrency sets how many chunks are fetched at the same time, within the rate limits of the client.
+// It defaults to 4.
+func WithBackfillConcurrency(n int) BackfillOption {
+	return func(c *backfillConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithGapFill delivers a filled candle for each bar without trades instead of counting it in Gap.
+// Bars before the first candle of the range cannot be filled without a previous close, unless the
+// backfill resumes from a checkpoint, and are still counted.
+func WithGapFill(fill bool) BackfillOption {
+	return func(c *backfillConfig) {
+		c.fillGaps = fill
+	}
+}
+
+// WithBackfillCheckpoint saves the progress after each chunk and resumes after the saved candle.
+// The key is the symbol and resolution, e.g. BTC_THB/60.
+func WithBackfillCheckpoint(checkpoint BackfillCheckpoint) BackfillOption {
+	return func(c *backfillConfig) {
+		c.checkpoint = checkpoint
+	}
+}
+
+type backfillChunk struct {
+	from, to time.Time
+}
+
+type backfillResult struct {
+	candles []model.Candle
+	err     error
+}
+
+// BackfillCandles delivers the candles of symbol from from to to, oldest first, to handler. The range
+// is split into chunks fetched concurrently, bars shared by two chunks are delivered once, and bars
+// without trades are either counted in Gap or filled, see WithGapFill. It stops at the first error,
+// including one returned by handler.
+//
+// Bars without trades at the start of the range are counted in the Gap of the first candle. Those
+// at the end are only delivered when filled; they are not saved in the checkpoint, so that a later
+// backfill resuming from it delivers them again, or counts them in the Gap of its first candle.
+//
+// With a checkpoint, a backfill which stopped resumes after the last saved candle. The candles of
+// the chunk being delivered when it stopped are delivered again, so store them by time.
+func (b *Client) BackfillCandles(ctx context.Context, symbol string, resolution Resolution, from, to time.Time, handler func(BackfillCandle) error, opts ...BackfillOption) error {
+	config := backfillConfig{chunk: 1000, concurrency: 4}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.chunk <= 0 || config.concurrency <= 0 {
+		return errors.New("backfill chunk and concurrency must be positive")
+	}
+	step := resolution.Duration()
+	if step == 0 {
+		return fmt.Errorf("unsupported resolution %q", resolution)
+	}
+
+	key := symbol + "/" + string(resolution)
+	var (
+		last     time.Time     // time of the last candle delivered
+		previous *model.Candle // last candle delivered, whose close fills the gaps
+	)
+	if config.checkpoint != nil {
+		saved, ok, err := config.checkpoint.Load(key)
+		if err != nil {
+			return err
+		}
+		if ok && !saved.Before(from) {
+			last = saved
+			from = saved.Add(step)
+			if config.fillGaps {
+				if previous, err = b.backfillSeed(ctx, symbol, resolution, saved); err != nil {
+					return fmt.Errorf("backfill %s: %w", key, err)
+				}
+			}
+		}
+	}
+	// time of the next bar, counting the bars without trades from the start of the range
+	next := bucketStart(from, step)
+	if next.Before(from) {
+		next = next.Add(step)
+	}
+
+	chunks := []backfillChunk{}
+	span := time.Duration(config.chunk) * step
+	for start := from; !start.After(to); start = start.Add(span) {
+		end := start.Add(span)
+		if end.After(to) {
+			end = to
+		}
+		chunks = append(chunks, backfillChunk{from: start, to: end})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan backfillResult, len(chunks))
+	for i := range results {
+		results[i] = make(chan backfillResult, 1)
+	}
+	started := 0
+	start := func() {
+		if started == len(chunks) {
+			return
+		}
+		chunk, result := chunks[started], results[started]
+		go func() {
+			candles, err := b.GetCandlesCtx(ctx, symbol, resolution, chunk.from, chunk.to)
+			result <- backfillResult{candles: candles, err: err}
+		}()
+		started++
+	}
+	for i := 0; i < config.concurrency; i++ {
+		start()
+	}
+
+	for i, chunk := range chunks {
+		var result backfillResult
+		select {
+		case result = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if result.err != nil {
+			return fmt.Errorf("backfill %s from %s: %w", key, chunk.from.Format(time.RFC3339), result.err)
+		}
+		start()
+
+		candles := result.candles
+		sort.SliceStable(candles, func(i, j int) bool { return candles[i].Time.Before(candles[j].Time) })
+		for _, candle := range candles {
+			if candle.Time.Before(from) || candle.Time.After(to) || (!last.IsZero() && !candle.Time.After(last)) {
+				continue
+			}
+
+			gap := int(candle.Time.Sub(next) / step)
+			if gap < 0 {
+				gap = 0
+			}
+			if config.fillGaps && previous != nil {
+				if err := fillBars(previous, next, gap, step, handler); err != nil {
+					return err
+				}
+				gap = 0
+			}
+
+			if err := handler(BackfillCandle{Candle: candle, Gap: gap}); err != nil {
+				return err
+			}
+			c := candle
+			previous, last, next = &c, candle.Time, candle.Time.Add(step)
+		}
+
+		if config.checkpoint != nil && !last.IsZero() {
+			if err := config.checkpoint.Save(key, last); err != nil {
+				return err
+			}
+		}
+	}
+
+	if config.fillGaps && previous != nil && !next.After(to) {
+		return fillBars(previous, next, int(to.Sub(next)/step)+1, step, handler)
+	}
+	return nil
+}
+
+// backfillSeed returns the candle at at, whose close fills the gaps after it, or nil when there is none.
+func (b *Client) backfillSeed(ctx context.Context, symbol string, resolution Resolution, at time.Time) (*model.Candle, error) {
+	candles, err := b.GetCandlesCtx(ctx, symbol, resolution, at, at)
+	if err != nil {
+		return nil, err
+	}
+	for i := range candles {
+		if candles[i].Time.Equal(at) {
+			return &candles[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// fillBars delivers count filled candles from start, with the close of previous.
+func fillBars(previous *model.Candle, start time.Time, count int, step time.Duration, handler func(BackfillCandle) error) error {
+	for i := 0; i < count; i++ {
+		filled := model.Candle{
+			Time:  start.Add(time.Duration(i) * step),
+			Open:  previous.Close,
+			High:  previous.Close,
+			Low:   previous.Close,
+			Close: previous.Close,
+		}
+		if err := handler(BackfillCandle{Candle: filled, Filled: true}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FileCheckpoint is a BackfillCheckpoint stored as JSON in a file.
+type FileCheckpoint struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileCheckpoint returns a checkpoint stored in path, which is created on the first save.
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{Path: path}
+}
+
+func (f *FileCheckpoint) Load(key string) (time.Time, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	saved, err := f.read()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	last, ok := saved[key]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	return time.Unix(last, 0).UTC(), true, nil
+}
+
+func (f *FileCheckpoint) Save(key string, last time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	saved, err := f.read()
+	if err != nil {
+		return err
+	}
+	saved[key] = last.Unix()
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	// write a temporary file first so that a crash cannot leave a truncated checkpoint
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+func (f *FileCheckpoint) read() (map[string]int64, error) {
+	saved := map[string]int64{}
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", f.Path, err)
+	}
+	return saved, nil
+}
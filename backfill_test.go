@@ -0,0 +1,175 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+)
+
+// historyServer serves 1-minute bars from minute 0 to 20, without minutes 7 and 8, whose close is the minute.
+func historyServer(t *testing.T, fail func(from int64) bool) (*bitkub.Client, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		if fail != nil && fail(from) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var ts, values []string
+		for m := from / 60; m <= to/60 && m <= 20; m++ {
+			if m == 7 || m == 8 {
+				continue
+			}
+			ts = append(ts, strconv.FormatInt(m*60, 10))
+			values = append(values, strconv.FormatInt(m, 10))
+		}
+		if len(ts) == 0 {
+			w.Write([]byte(`{"s":"no_data"}`))
+			return
+		}
+		v := strings.Join(values, ",")
+		fmt.Fprintf(w, `{"s":"ok","t":[%s],"o":[%s],"h":[%s],"l":[%s],"c":[%s],"v":[%s]}`, strings.Join(ts, ","), v, v, v, v, v)
+	}))
+	t.Cleanup(srv.Close)
+	return bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil), bitkub.WithRetryPolicy(nil)), &requests
+}
+
+func minute(m int) time.Time {
+	return time.Unix(int64(m)*60, 0).UTC()
+}
+
+func TestBackfillCandles(t *testing.T) {
+	api, requests := historyServer(t, nil)
+
+	var got []string
+	err := api.BackfillCandles(context.Background(), "BTC_THB", bitkub.Resolution1m, minute(2), minute(12), func(c bitkub.BackfillCandle) error {
+		got = append(got, fmt.Sprintf("%d/%s/%d", c.Time.Unix()/60, c.Close, c.Gap))
+		return nil
+	}, bitkub.WithBackfillChunk(3), bitkub.WithBackfillConcurrency(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "2/2/0 3/3/0 4/4/0 5/5/0 6/6/0 9/9/2 10/10/0 11/11/0 12/12/0"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("got %v, want %s", got, want)
+	}
+	if n := atomic.LoadInt32(requests); n != 4 {
+		t.Fatalf("expected 4 chunks, got %d requests", n)
+	}
+}
+
+func TestBackfillCandlesFillGaps(t *testing.T) {
+	api, _ := historyServer(t, nil)
+
+	var got []string
+	err := api.BackfillCandles(context.Background(), "BTC_THB", bitkub.Resolution1m, minute(5), minute(10), func(c bitkub.BackfillCandle) error {
+		got = append(got, fmt.Sprintf("%d/%s/%t", c.Time.Unix()/60, c.Close, c.Filled))
+		return nil
+	}, bitkub.WithGapFill(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "5/5/false 6/6/false 7/6/true 8/6/true 9/9/false 10/10/false"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("got %v, want %s", got, want)
+	}
+}
+
+func TestBackfillCandlesResume(t *testing.T) {
+	var failing int32 = 1
+	api, _ := historyServer(t, func(from int64) bool {
+		return from >= 9*60 && atomic.LoadInt32(&failing) == 1
+	})
+	checkpoint := bitkub.NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	var got []int64
+	backfill := func() error {
+		return api.BackfillCandles(context.Background(), "BTC_THB", bitkub.Resolution1m, minute(0), minute(14), func(c bitkub.BackfillCandle) error {
+			got = append(got, c.Time.Unix()/60)
+			return nil
+		}, bitkub.WithBackfillChunk(3), bitkub.WithBackfillCheckpoint(checkpoint))
+	}
+
+	var apiErr *bitkub.APIError
+	if err := backfill(); !errors.As(err, &apiErr) {
+		t.Fatalf("expected the chunk from minute 9 to fail, got %v", err)
+	}
+	if last, ok, err := checkpoint.Load("BTC_THB/1"); err != nil || !ok || !last.Equal(minute(9)) {
+		t.Fatalf("unexpected checkpoint %s, %t, %v", last, ok, err)
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	got = nil
+	if err := backfill(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[10 11 12 13 14]" {
+		t.Fatalf("unexpected resumed candles %v", got)
+	}
+}
+
+func TestBackfillCandlesResumeFillGaps(t *testing.T) {
+	api, _ := historyServer(t, nil)
+	checkpoint := bitkub.NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"))
+	if err := checkpoint.Save("BTC_THB/1", minute(6)); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	err := api.BackfillCandles(context.Background(), "BTC_THB", bitkub.Resolution1m, minute(0), minute(10), func(c bitkub.BackfillCandle) error {
+		got = append(got, fmt.Sprintf("%d/%s/%t", c.Time.Unix()/60, c.Close, c.Filled))
+		return nil
+	}, bitkub.WithGapFill(true), bitkub.WithBackfillCheckpoint(checkpoint))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the gap right after the checkpoint is filled with the close of the saved candle
+	want := "7/6/true 8/6/true 9/9/false 10/10/false"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("got %v, want %s", got, want)
+	}
+}
+
+func TestBackfillCandlesEdgeGaps(t *testing.T) {
+	api, _ := historyServer(t, nil)
+	backfill := func(from, to int, opts ...bitkub.BackfillOption) string {
+		var got []string
+		err := api.BackfillCandles(context.Background(), "BTC_THB", bitkub.Resolution1m, minute(from), minute(to), func(c bitkub.BackfillCandle) error {
+			got = append(got, fmt.Sprintf("%d/%s/%d/%t", c.Time.Unix()/60, c.Close, c.Gap, c.Filled))
+			return nil
+		}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Join(got, " ")
+	}
+
+	// minutes 7 and 8 have no trades, and no bar was seen before them
+	for _, fill := range []bool{false, true} {
+		if got := backfill(7, 10, bitkub.WithGapFill(fill)); got != "9/9/2/false 10/10/0/false" {
+			t.Fatalf("unexpected leading gap with fill %t: %s", fill, got)
+		}
+	}
+	// minutes 21 and 22 have no trades yet
+	if got := backfill(19, 22); got != "19/19/0/false 20/20/0/false" {
+		t.Fatalf("unexpected trailing gap: %s", got)
+	}
+	if got := backfill(19, 22, bitkub.WithGapFill(true)); got != "19/19/0/false 20/20/0/false 21/20/0/true 22/20/0/true" {
+		t.Fatalf("unexpected filled trailing gap: %s", got)
+	}
+}
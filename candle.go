@@ -0,0 +1,90 @@
+package bitkub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+const tradingViewHistoryEndpoint = "/tradingview/history"
+
+// Resolution is the interval of the candles of GetCandles.
+type Resolution string
+
+// Resolutions supported by /tradingview/history.
+const (
+	Resolution1m  Resolution = "1"
+	Resolution5m  Resolution = "5"
+	Resolution15m Resolution = "15"
+	Resolution1h  Resolution = "60"
+	Resolution4h  Resolution = "240"
+	Resolution1D  Resolution = "1D"
+)
+
+// Resolutions lists the supported resolutions, shortest first.
+var Resolutions = []Resolution{Resolution1m, Resolution5m, Resolution15m, Resolution1h, Resolution4h, Resolution1D}
+
+// Duration returns the length of a candle, 0 for an unsupported resolution.
+func (r Resolution) Duration() time.Duration {
+	switch r {
+	case Resolution1m:
+		return time.Minute
+	case Resolution5m:
+		return 5 * time.Minute
+	case Resolution15m:
+		return 15 * time.Minute
+	case Resolution1h:
+		return time.Hour
+	case Resolution4h:
+		return 4 * time.Hour
+	case Resolution1D:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// GetCandles returns the candles of symbol, e.g. BTC_THB, starting between from and to, oldest first.
+// It returns no candles when there is no data in the range.
+func (b *Client) GetCandles(symbol string, resolution Resolution, from, to time.Time) ([]model.Candle, error) {
+	return b.GetCandlesCtx(context.Background(), symbol, resolution, from, to)
+}
+
+// GetCandlesCtx is like GetCandles but carries ctx for cancellation and deadlines.
+func (b *Client) GetCandlesCtx(ctx context.Context, symbol string, resolution Resolution, from, to time.Time) ([]model.Candle, error) {
+	history, err := b.tradingViewHistory(ctx, symbol, resolution, from, to)
+	if err != nil {
+		return nil, err
+	}
+	candles, err := history.Candles()
+	if err != nil {
+		if history.Status == model.HistoryStatusError {
+			return nil, err
+		}
+		return nil, &DecodeError{Endpoint: tradingViewHistoryEndpoint, Err: err}
+	}
+	return candles, nil
+}
+
+func (b *Client) tradingViewHistory(ctx context.Context, symbol string, resolution Resolution, from, to time.Time) (*model.TradingViewHistory, error) {
+	if resolution.Duration() == 0 {
+		return nil, fmt.Errorf("unsupported resolution %q", resolution)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("to %s is before from %s", to, from)
+	}
+
+	params := []string{
+		"sym=" + symbol,
+		"resolution=" + string(resolution),
+		fmt.Sprintf("from=%d", from.Unix()),
+		fmt.Sprintf("to=%d", to.Unix()),
+	}
+	ret := model.TradingViewHistory{}
+	if err := b.get(ctx, tradingViewHistoryEndpoint+"?"+strings.Join(params, "&"), &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
@@ -0,0 +1,53 @@
+package bitkub_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+)
+
+func TestGetCandles(t *testing.T) {
+	responses := []string{
+		`{"s":"ok","t":[1633424400,1633428000],"o":[1000,1010.5],"h":[1020,1030],"l":[990,1000],"c":[1010.5,1025],"v":[0.5,1.25]}`,
+		`{"s":"no_data","nextTime":1633420800}`,
+		`{"s":"ok","t":[1633424400,1633428000],"o":[1000],"h":[1020],"l":[990],"c":[1010.5],"v":[0.5]}`,
+	}
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(responses[0]))
+		responses = responses[1:]
+	}))
+	defer srv.Close()
+
+	api := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil))
+	from, to := time.Unix(1633424400, 0), time.Unix(1633431600, 0)
+
+	candles, err := api.GetCandles("BTC_THB", bitkub.Resolution1h, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "sym=BTC_THB&resolution=60&from=1633424400&to=1633431600" {
+		t.Fatalf("unexpected query %s", query)
+	}
+	if len(candles) != 2 || !candles[1].Time.Equal(time.Unix(1633428000, 0)) || candles[1].Open.String() != "1010.5" || candles[1].Volume.String() != "1.25" {
+		t.Fatalf("unexpected candles %+v", candles)
+	}
+
+	if candles, err := api.GetCandles("BTC_THB", bitkub.Resolution1h, from, to); err != nil || len(candles) != 0 {
+		t.Fatalf("expected no candles, got %+v, %v", candles, err)
+	}
+
+	var decodeErr *bitkub.DecodeError
+	if _, err := api.GetCandles("BTC_THB", bitkub.Resolution1h, from, to); !errors.As(err, &decodeErr) {
+		t.Fatalf("expected a DecodeError, got %v", err)
+	}
+
+	if _, err := api.GetCandles("BTC_THB", "2", from, to); err == nil {
+		t.Fatal("expected an error for an unsupported resolution")
+	}
+}
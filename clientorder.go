@@ -0,0 +1,230 @@
+package bitkub
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// ErrOrderNotFound is returned by FindOrder when no order carries the client id.
+var ErrOrderNotFound = errors.New("order not found")
+
+// lookupEndpoint is the endpoint of the RetryAttempt passed to the order lookup policy, see WithOrderLookupPolicy.
+const lookupEndpoint = "FindOrder"
+
+// DefaultOrderLookupPolicy returns the policy used by PlaceOrRecover to look an order up until it
+// is listed, unless set with WithOrderLookupPolicy.
+func DefaultOrderLookupPolicy() *BackoffPolicy {
+	return &BackoffPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    8 * time.Second,
+	}
+}
+
+// UnknownOutcomeError is returned by PlaceOrRecover when an order may have been placed but could not
+// be found by its client id. Sending it again could place it twice, so look it up later with
+// FindOrder before deciding. It wraps the error of the order.
+type UnknownOutcomeError struct {
+	ClientID string
+	Err      error // error of the order
+	FindErr  error // error of the last lookup, wrapping ErrOrderNotFound when the order was not listed
+}
+
+func (e *UnknownOutcomeError) Error() string {
+	return fmt.Sprintf("order with client id %s may have been placed: %v, lookup: %v", e.ClientID, e.Err, e.FindErr)
+}
+
+func (e *UnknownOutcomeError) Unwrap() error {
+	return e.Err
+}
+
+// NewClientID returns a random client order id, starting with the time so that ids sort by creation.
+func NewClientID() string {
+	random := make([]byte, 6)
+	if _, err := rand.Read(random); err != nil {
+		panic(fmt.Sprintf("bitkub: generate client id: %v", err))
+	}
+	return strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 36) + hex.EncodeToString(random)
+}
+
+// clientIDOf returns the client id of req, generating it when the client has a generator or always is set.
+func (b *Client) clientIDOf(req *OrderRequest, always bool) string {
+	if req.clientID != "" {
+		return req.clientID
+	}
+	if b.clientID != nil {
+		return b.clientID()
+	}
+	if always {
+		return NewClientID()
+	}
+	return ""
+}
+
+// ClientOrder is an order found by its client id, see FindOrder.
+type ClientOrder struct {
+	ID        string
+	Hash      string
+	ClientID  string
+	Side      string
+	Type      string
+	Rate      decimal.Decimal
+	Amount    decimal.Decimal
+	Open      bool  // listed by GetOpenOrder, otherwise found in the order history
+	Timestamp int64 // timestamp of the order, or of its last fill
+}
+
+// FindOrder finds the order of symbol carrying clientID, first among the open orders, then in the
+// order history since since, newest first. It returns ErrOrderNotFound when there is none. The v3
+// endpoints are used since only they report client ids.
+func (v *ClientV3) FindOrder(ctx context.Context, symbol, clientID string, since time.Time) (*ClientOrder, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("client id is empty")
+	}
+	s, err := ParseSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := v.GetOpenOrder(ctx, s.V3())
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range open {
+		if order.ClientID == clientID {
+			return &ClientOrder{
+				ID:        order.ID,
+				Hash:      order.Hash,
+				ClientID:  order.ClientID,
+				Side:      order.Side,
+				Type:      order.Type,
+				Rate:      order.Rate,
+				Amount:    order.Amount,
+				Open:      true,
+				Timestamp: order.Timestamp,
+			}, nil
+		}
+	}
+
+	for page := 1; ; page++ {
+		history, pagination, err := v.GetOrderHistory(ctx, s.V3(), page, defaultPageSize, sinceUnix(since), 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range history {
+			if order.ClientID == clientID {
+				return &ClientOrder{
+					ID:        order.OrderID,
+					Hash:      order.Hash,
+					ClientID:  order.ClientID,
+					Side:      order.Side,
+					Type:      order.Type,
+					Rate:      order.Rate,
+					Amount:    order.Amount,
+					Timestamp: order.Timestamp,
+				}, nil
+			}
+		}
+		if len(history) == 0 || pagination == nil || page >= pagination.Last {
+			return nil, fmt.Errorf("%w: client id %s of %s", ErrOrderNotFound, clientID, symbol)
+		}
+	}
+}
+
+// FindOrder is like ClientV3.FindOrder.
+func (b *Client) FindOrder(ctx context.Context, symbol, clientID string, since time.Time) (*ClientOrder, error) {
+	return b.V3().FindOrder(ctx, symbol, clientID, since)
+}
+
+// PlaceOrRecover places the order like PlaceOrder, with a generated client id when it has none.
+// When the outcome is unknown, e.g. after a timeout or a server error, the order is never sent
+// again: it is looked up by its client id with FindOrder, with the backoff of the order lookup
+// policy since a request in flight or a recent order may not be listed yet. An order which could
+// not be found fails with an *UnknownOutcomeError carrying the client id. The retry policy of the
+// client is not applied to such failures. Failures before the order was sent, e.g. of a fail-fast
+// rate limiter, are returned as they are. It reports whether the order was recovered rather than
+// placed; a recovered order only has the fields of ClientOrder.
+func (b *Client) PlaceOrRecover(ctx context.Context, req *OrderRequest) (*model.Order, bool, error) {
+	placed := *req
+	placed.clientID = b.clientIDOf(req, true)
+	// the order history is filtered by the server time, with some slack
+	since := b.now().Add(-time.Minute)
+
+	order, err := placed.resolve(ctx, b, false, b.GetBalancesCtx)
+	if err != nil {
+		return nil, false, err
+	}
+	sent, err := b.sendOrder(context.WithValue(ctx, noResendKey{}, true), &placed, order)
+	if err == nil || !outcomeUnknown(err) || placed.test {
+		return sent, false, err
+	}
+
+	found, findErr := b.lookupOrder(ctx, placed.symbol, placed.clientID, since)
+	if findErr != nil {
+		return nil, false, &UnknownOutcomeError{ClientID: placed.clientID, Err: err, FindErr: findErr}
+	}
+	id, _ := strconv.ParseInt(found.ID, 10, 64)
+	return &model.Order{
+		ID:        id,
+		Hash:      found.Hash,
+		Type:      found.Type,
+		Amount:    found.Amount,
+		Rate:      found.Rate,
+		Timestamp: found.Timestamp,
+		ClientID:  found.ClientID,
+	}, true, nil
+}
+
+// lookupOrder calls FindOrder until the order is found or the order lookup policy gives up.
+func (b *Client) lookupOrder(ctx context.Context, symbol, clientID string, since time.Time) (*ClientOrder, error) {
+	policy := b.lookup
+	if policy == nil {
+		policy = DefaultOrderLookupPolicy()
+	}
+	for attempt := 1; ; attempt++ {
+		found, err := b.FindOrder(ctx, symbol, clientID, since)
+		if err == nil {
+			return found, nil
+		}
+		wait, retry := policy.Retry(RetryAttempt{Endpoint: lookupEndpoint, Idempotent: true, Attempt: attempt, Err: err})
+		if !retry {
+			return nil, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// outcomeUnknown reports whether an order failing with err may still have been placed. Errors
+// occurring before the order was written, e.g. from the rate limiter, signing or dialing, are
+// definite failures.
+func outcomeUnknown(err error) bool {
+	var notSent *internal.NotSentError
+	if errors.As(err, &notSent) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Code != 0 {
+			return apiErr.Is(ErrServerError)
+		}
+		return apiErr.HTTPStatus >= http.StatusInternalServerError
+	}
+	return true
+}
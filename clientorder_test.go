@@ -0,0 +1,220 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+func TestNewClientID(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := bitkub.NewClientID()
+		if id == "" || seen[id] {
+			t.Fatalf("unexpected client id %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+// recoverServer fails the orders in failures: "lost" places the order and answers an HTTP 500,
+// "late" does too but the order is only listed by the third lookup, "failed" answers an HTTP 500
+// without placing it and "rejected" answers the error 15.
+func recoverServer(t *testing.T, failures []string, opts ...bitkub.Option) (*bitkub.Client, *[]string) {
+	var (
+		mu        sync.Mutex
+		clientIDs []string
+		open      []string
+		late      []string
+		lookups   int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/market/place-bid", func(w http.ResponseWriter, r *http.Request) {
+		clientID, _ := payloadOf(r)["client_id"].(string)
+
+		mu.Lock()
+		defer mu.Unlock()
+		clientIDs = append(clientIDs, clientID)
+		failure := ""
+		if len(failures) > 0 {
+			failure, failures = failures[0], failures[1:]
+		}
+		switch failure {
+		case "lost":
+			open = append(open, clientID)
+			w.WriteHeader(http.StatusInternalServerError)
+		case "late":
+			late = append(late, clientID)
+			w.WriteHeader(http.StatusInternalServerError)
+		case "failed":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "rejected":
+			w.Write([]byte(`{"error":15}`))
+		default:
+			open = append(open, clientID)
+			fmt.Fprintf(w, `{"error":0,"result":{"id":%d,"hash":"placed","typ":"limit","amt":100,"rat":1000000}}`, len(open))
+		}
+	})
+	mux.HandleFunc("/api/v3/market/my-open-orders", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sym") != "btc_thb" {
+			t.Errorf("unexpected symbol %s", r.URL.Query().Get("sym"))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if lookups++; lookups == 3 {
+			open, late = append(open, late...), nil
+		}
+		orders := []string{}
+		for i, clientID := range open {
+			orders = append(orders, fmt.Sprintf(`{"id":"%d","hash":"found","side":"buy","type":"limit","rate":1000000,"amount":100,"client_id":"%s"}`, i+1, clientID))
+		}
+		fmt.Fprintf(w, `{"error":0,"result":[%s]}`, strings.Join(orders, ","))
+	})
+	mux.HandleFunc("/api/v3/market/my-order-history", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":0,"result":[],"pagination":{"page":1,"last":1}}`))
+	})
+	fastLookup := bitkub.WithOrderLookupPolicy(&bitkub.BackoffPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+	return testClient(t, mux, append([]bitkub.Option{fastRetry(1), fastLookup}, opts...)...), &clientIDs
+}
+
+// retryAll retries every failed request three times, even when it may have been executed.
+type retryAll struct{}
+
+func (retryAll) Retry(a bitkub.RetryAttempt) (time.Duration, bool) {
+	return 0, a.Attempt < 3
+}
+
+func TestPlaceOrRecover(t *testing.T) {
+	ctx := context.Background()
+	req := func() *bitkub.OrderRequest {
+		return bitkub.BuyOrder("THB_BTC").Limit(decimal.NewFromInt(1000000)).Amount(decimal.NewFromInt(100))
+	}
+
+	t.Run("placed", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, nil)
+		order, recovered, err := api.PlaceOrRecover(ctx, req().ClientID("my-1"))
+		if err != nil || recovered || order.Hash != "placed" || order.ClientID != "my-1" || strings.Join(*clientIDs, ",") != "my-1" {
+			t.Fatalf("unexpected order %+v %v %v, sent %v", order, recovered, err, *clientIDs)
+		}
+	})
+
+	t.Run("recovered", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, []string{"lost"})
+		order, recovered, err := api.PlaceOrRecover(ctx, req())
+		if err != nil || !recovered || order.ID != 1 || order.Hash != "found" {
+			t.Fatalf("unexpected order %+v %v %v", order, recovered, err)
+		}
+		if len(*clientIDs) != 1 || (*clientIDs)[0] == "" || order.ClientID != (*clientIDs)[0] {
+			t.Fatalf("expected one order with the generated client id %s, sent %v", order.ClientID, *clientIDs)
+		}
+	})
+
+	t.Run("recovered with the default retry policy", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, []string{"lost"}, bitkub.WithRetryPolicy(bitkub.DefaultRetryPolicy()))
+		order, recovered, err := api.PlaceOrRecover(ctx, req())
+		if err != nil || !recovered || order.Hash != "found" || len(*clientIDs) != 1 {
+			t.Fatalf("unexpected order %+v %v %v, sent %v", order, recovered, err, *clientIDs)
+		}
+	})
+
+	t.Run("recovered with a policy retrying everything", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, []string{"lost"}, bitkub.WithRetryPolicy(retryAll{}))
+		order, recovered, err := api.PlaceOrRecover(ctx, req())
+		if err != nil || !recovered || order.Hash != "found" || len(*clientIDs) != 1 {
+			t.Fatalf("unexpected order %+v %v %v, sent %v", order, recovered, err, *clientIDs)
+		}
+	})
+
+	t.Run("listed late", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, []string{"late"})
+		order, recovered, err := api.PlaceOrRecover(ctx, req())
+		if err != nil || !recovered || order.Hash != "found" || len(*clientIDs) != 1 {
+			t.Fatalf("unexpected order %+v %v %v, sent %v", order, recovered, err, *clientIDs)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, []string{"failed"})
+		_, _, err := api.PlaceOrRecover(ctx, req().ClientID("my-1"))
+		var unknown *bitkub.UnknownOutcomeError
+		if !errors.As(err, &unknown) || unknown.ClientID != "my-1" || !errors.Is(unknown.FindErr, bitkub.ErrOrderNotFound) {
+			t.Fatalf("expected an unknown outcome, got %v", err)
+		}
+		if strings.Join(*clientIDs, ",") != "my-1" {
+			t.Fatalf("expected the order to be sent once, sent %v", *clientIDs)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, []string{"rejected"})
+		if _, _, err := api.PlaceOrRecover(ctx, req()); !errors.Is(err, bitkub.ErrAmountTooLow) || len(*clientIDs) != 1 {
+			t.Fatalf("unexpected error %v, sent %v", err, *clientIDs)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		limiter := bitkub.NewRateLimiter(map[bitkub.RateLimitGroup]bitkub.RateLimit{bitkub.RateLimitTrade: {Rate: 0.001, Burst: 1}}, true)
+		api, clientIDs := recoverServer(t, nil, bitkub.WithRateLimiter(limiter))
+		if _, _, err := api.PlaceOrRecover(ctx, req()); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := api.PlaceOrRecover(ctx, req())
+		var unknown *bitkub.UnknownOutcomeError
+		if !errors.Is(err, bitkub.ErrRateLimited) || errors.As(err, &unknown) || len(*clientIDs) != 1 {
+			t.Fatalf("expected a definite failure, got %v, sent %v", err, *clientIDs)
+		}
+	})
+
+	t.Run("cancelled before sending", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, nil)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, _, err := api.PlaceOrRecover(cancelled, req())
+		var unknown *bitkub.UnknownOutcomeError
+		if !errors.Is(err, context.Canceled) || errors.As(err, &unknown) || len(*clientIDs) != 0 {
+			t.Fatalf("expected a definite failure, got %v, sent %v", err, *clientIDs)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+		api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil), fastRetry(1))
+		_, _, err := api.PlaceOrRecover(ctx, req())
+		var unknown *bitkub.UnknownOutcomeError
+		if err == nil || errors.As(err, &unknown) {
+			t.Fatalf("expected a definite failure, got %v", err)
+		}
+	})
+
+	t.Run("generator", func(t *testing.T) {
+		api, clientIDs := recoverServer(t, nil, bitkub.WithClientIDGenerator(func() string { return "generated" }))
+		order, err := api.PlaceOrder(req())
+		if err != nil || order.ClientID != "generated" || strings.Join(*clientIDs, ",") != "generated" {
+			t.Fatalf("unexpected order %+v %v, sent %v", order, err, *clientIDs)
+		}
+	})
+}
+
+func TestFindOrder(t *testing.T) {
+	api, _ := recoverServer(t, nil)
+	if _, err := api.PlaceOrder(bitkub.BuyOrder("THB_BTC").Limit(decimal.NewFromInt(1000000)).Amount(decimal.NewFromInt(100)).ClientID("my-1")); err != nil {
+		t.Fatal(err)
+	}
+	order, err := api.FindOrder(context.Background(), "THB_BTC", "my-1", time.Time{})
+	if err != nil || !order.Open || order.ID != "1" {
+		t.Fatalf("unexpected order %+v %v", order, err)
+	}
+	if _, err := api.FindOrder(context.Background(), "btc_thb", "my-2", time.Time{}); !errors.Is(err, bitkub.ErrOrderNotFound) {
+		t.Fatalf("expected ErrOrderNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package bitkub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// clock keeps the offset between the local clock and the Bitkub server used to sign payloads.
+type clock struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	offset   time.Duration
+	rtt      time.Duration
+	syncedAt time.Time
+	syncing  bool
+}
+
+// SyncClock measures the offset between the local clock and the Bitkub server and applies it to
+// the timestamp of signed payloads. With WithClockSync it is called when a payload is about to be
+// signed and the last sync is older than the interval, and after an "Invalid timestamp" error.
+// The server time is read from the v3 endpoint, which unlike GetServerTime has millisecond precision.
+func (b *Client) SyncClock(ctx context.Context) error {
+	start := time.Now()
+	serverTime, err := b.V3().GetServerTime(ctx)
+	if err != nil {
+		return err
+	}
+	end := time.Now()
+
+	rtt := end.Sub(start)
+	offset := serverTime.Sub(start.Add(rtt / 2))
+
+	b.clock.mu.Lock()
+	defer b.clock.mu.Unlock()
+	b.clock.offset, b.clock.rtt, b.clock.syncedAt = offset, rtt, end
+	return nil
+}
+
+// ClockOffset returns how far the Bitkub server clock is ahead of the local clock, as of the last sync.
+func (b *Client) ClockOffset() time.Duration {
+	b.clock.mu.Lock()
+	defer b.clock.mu.Unlock()
+	return b.clock.offset
+}
+
+// ClockRTT returns the round trip time measured by the last sync.
+func (b *Client) ClockRTT() time.Duration {
+	b.clock.mu.Lock()
+	defer b.clock.mu.Unlock()
+	return b.clock.rtt
+}
+
+// now returns the local time corrected by the clock offset.
+func (b *Client) now() time.Time {
+	return time.Now().Add(b.ClockOffset())
+}
+
+// maybeSyncClock syncs the clock when clock sync is enabled. The first sync blocks so the very
+// first payload is already signed with the server time, later ones run in the background.
+func (b *Client) maybeSyncClock(ctx context.Context) {
+	if b.clock.interval <= 0 {
+		return
+	}
+
+	b.clock.mu.Lock()
+	stale := time.Since(b.clock.syncedAt) >= b.clock.interval
+	first := b.clock.syncedAt.IsZero()
+	if !stale || b.clock.syncing {
+		b.clock.mu.Unlock()
+		return
+	}
+	b.clock.syncing = true
+	b.clock.mu.Unlock()
+
+	due := func(ctx context.Context) {
+		defer func() {
+			b.clock.mu.Lock()
+			b.clock.syncing = false
+			b.clock.mu.Unlock()
+		}()
+		// on failure the previous offset is kept and the next payload tries again
+		_ = b.SyncClock(ctx)
+	}
+
+	if first {
+		due(ctx)
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), b.Timeout)
+		defer cancel()
+		due(ctx)
+	}()
+}
+
+// resyncOnInvalidTimestamp forces a clock sync before the payload is signed again.
+func (b *Client) resyncOnInvalidTimestamp(ctx context.Context, err error) {
+	if b.clock.interval > 0 && errors.Is(err, ErrInvalidTimestamp) {
+		_ = b.SyncClock(ctx)
+	}
+}
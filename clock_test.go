@@ -0,0 +1,60 @@
+package bitkub_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+)
+
+func TestClockSync(t *testing.T) {
+	const drift = time.Hour
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverNow := time.Now().Add(drift)
+		switch r.URL.Path {
+		case "/api/servertime":
+			fmt.Fprintf(w, "%d", serverNow.Unix())
+		case "/api/v3/servertime":
+			fmt.Fprintf(w, "%d", serverNow.UnixMilli())
+		case "/api/user/trading-credits":
+			payload := struct {
+				Ts int64 `json:"ts"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Error(err)
+			}
+			if d := serverNow.Sub(time.UnixMilli(payload.Ts)); d > 2*time.Second || d < -2*time.Second {
+				w.Write([]byte(`{"error":8}`))
+				return
+			}
+			w.Write([]byte(`{"error":0,"result":10}`))
+		}
+	}))
+	defer srv.Close()
+
+	api := bitkub.NewClient("key", "secret",
+		bitkub.WithBaseURL(srv.URL),
+		bitkub.WithRetryPolicy(nil),
+		bitkub.WithClockSync(time.Minute),
+	)
+
+	serverTime, err := api.GetServerTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(serverTime) - drift; d > 2*time.Second || d < -2*time.Second {
+		t.Fatalf("unexpected server time %s", serverTime)
+	}
+
+	if _, err := api.GetUserTradingCredits(); err != nil {
+		t.Fatal(err)
+	}
+	// the v3 server time has millisecond precision
+	if d := api.ClockOffset() - drift; d > 100*time.Millisecond || d < -100*time.Millisecond {
+		t.Fatalf("unexpected clock offset %s", api.ClockOffset())
+	}
+}
@@ -0,0 +1,397 @@
+// Package decimal implements exact decimal numbers for amounts, rates and fees.
+package decimal
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DivisionPrecision is the number of decimal places kept by Div.
+var DivisionPrecision int32 = 16
+
+// Zero is the decimal 0. It equals the zero value of Decimal.
+var Zero = Decimal{}
+
+var ten = big.NewInt(10)
+
+// Decimal is an arbitrary-precision decimal number, value * 10^exp. Decimals are immutable and
+// the zero value is 0.
+type Decimal struct {
+	value *big.Int
+	exp   int32
+}
+
+// New returns value * 10^exp.
+func New(value int64, exp int32) Decimal {
+	return Decimal{value: big.NewInt(value), exp: exp}
+}
+
+// NewFromInt returns the decimal of an integer.
+func NewFromInt(value int64) Decimal {
+	return New(value, 0)
+}
+
+// NewFromBigInt returns value * 10^exp.
+func NewFromBigInt(value *big.Int, exp int32) Decimal {
+	return Decimal{value: new(big.Int).Set(value), exp: exp}
+}
+
+// NewFromFloat returns the shortest decimal representing f, e.g. 0.1 rather than 0.1000000000000000055511151231257827.
+// It panics if f is NaN or infinite.
+func NewFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("decimal: cannot create a decimal from %v", f))
+	}
+	d, err := NewFromString(strconv.FormatFloat(f, 'g', -1, 64))
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewFromString parses a decimal such as "-12.345", "0.00000001" or "1.5e-3".
+func NewFromString(s string) (Decimal, error) {
+	str := s
+
+	var exp int64
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("decimal: invalid number %q", s)
+		}
+		exp = e
+		str = str[:i]
+	}
+
+	sign := ""
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		sign, str = str[:1], str[1:]
+	}
+
+	intPart, fracPart := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		intPart, fracPart = str[:i], str[i+1:]
+	}
+	digits := intPart + fracPart
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, fmt.Errorf("decimal: invalid number %q", s)
+	}
+
+	value, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("decimal: invalid number %q", s)
+	}
+	exp -= int64(len(fracPart))
+	if exp < math.MinInt32 || exp > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("decimal: exponent of %q out of range", s)
+	}
+	return Decimal{value: value, exp: int32(exp)}, nil
+}
+
+// RequireFromString is like NewFromString but panics on error. It is meant for constants.
+func RequireFromString(s string) Decimal {
+	d, err := NewFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Min returns the smallest of the given decimals.
+func Min(first Decimal, rest ...Decimal) Decimal {
+	min := first
+	for _, d := range rest {
+		if d.Cmp(min) < 0 {
+			min = d
+		}
+	}
+	return min
+}
+
+// Max returns the largest of the given decimals.
+func Max(first Decimal, rest ...Decimal) Decimal {
+	max := first
+	for _, d := range rest {
+		if d.Cmp(max) > 0 {
+			max = d
+		}
+	}
+	return max
+}
+
+func (d Decimal) val() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(ten, big.NewInt(int64(n)), nil)
+}
+
+// rescale returns d with the given smaller or equal exponent.
+func (d Decimal) rescale(exp int32) Decimal {
+	if exp >= d.exp {
+		return Decimal{value: d.val(), exp: d.exp}
+	}
+	return Decimal{value: new(big.Int).Mul(d.val(), pow10(d.exp-exp)), exp: exp}
+}
+
+func align(d, d2 Decimal) (Decimal, Decimal) {
+	if d.exp < d2.exp {
+		return d, d2.rescale(d.exp)
+	}
+	return d.rescale(d2.exp), d2
+}
+
+// Add returns d + d2.
+func (d Decimal) Add(d2 Decimal) Decimal {
+	a, b := align(d, d2)
+	return Decimal{value: new(big.Int).Add(a.val(), b.val()), exp: a.exp}
+}
+
+// Sub returns d - d2.
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	a, b := align(d, d2)
+	return Decimal{value: new(big.Int).Sub(a.val(), b.val()), exp: a.exp}
+}
+
+// Mul returns d * d2.
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	return Decimal{value: new(big.Int).Mul(d.val(), d2.val()), exp: d.exp + d2.exp}
+}
+
+// Div returns d / d2 rounded to DivisionPrecision decimal places. It panics if d2 is zero.
+func (d Decimal) Div(d2 Decimal) Decimal {
+	return d.DivRound(d2, DivisionPrecision)
+}
+
+// DivRound returns d / d2 rounded half away from zero to the given decimal places. It panics if d2 is zero.
+func (d Decimal) DivRound(d2 Decimal, places int32) Decimal {
+	if d2.IsZero() {
+		panic("decimal: division by zero")
+	}
+
+	num, den := new(big.Int).Set(d.val()), new(big.Int).Set(d2.val())
+	if k := d.exp - d2.exp + places; k >= 0 {
+		num.Mul(num, pow10(k))
+	} else {
+		den.Mul(den, pow10(-k))
+	}
+	return Decimal{value: quoRound(num, den), exp: -places}
+}
+
+// quoRound returns num / den rounded half away from zero.
+func quoRound(num, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	r2 := new(big.Int).Abs(r)
+	r2.Lsh(r2, 1)
+	if r2.Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign() == den.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// quantize returns d with places decimal places, adjusting the truncated value with adjust when digits are dropped.
+func (d Decimal) quantize(places int32, adjust func(q, r *big.Int, factor *big.Int)) Decimal {
+	if d.exp >= -places {
+		return d
+	}
+	factor := pow10(-places - d.exp)
+	q, r := new(big.Int).QuoRem(d.val(), factor, new(big.Int))
+	if r.Sign() != 0 {
+		adjust(q, r, factor)
+	}
+	return Decimal{value: q, exp: -places}
+}
+
+// Round rounds d half away from zero to the given decimal places.
+func (d Decimal) Round(places int32) Decimal {
+	return d.quantize(places, func(q, r, factor *big.Int) {
+		r2 := new(big.Int).Abs(r)
+		if r2.Lsh(r2, 1).Cmp(factor) >= 0 {
+			q.Add(q, big.NewInt(int64(r.Sign())))
+		}
+	})
+}
+
+// Truncate rounds d toward zero to the given decimal places.
+func (d Decimal) Truncate(places int32) Decimal {
+	return d.quantize(places, func(q, r, factor *big.Int) {})
+}
+
+// RoundFloor rounds d toward negative infinity to the given decimal places.
+func (d Decimal) RoundFloor(places int32) Decimal {
+	return d.quantize(places, func(q, r, factor *big.Int) {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		}
+	})
+}
+
+// RoundCeil rounds d toward positive infinity to the given decimal places.
+func (d Decimal) RoundCeil(places int32) Decimal {
+	return d.quantize(places, func(q, r, factor *big.Int) {
+		if r.Sign() > 0 {
+			q.Add(q, big.NewInt(1))
+		}
+	})
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.val()), exp: d.exp}
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: new(big.Int).Abs(d.val()), exp: d.exp}
+}
+
+// Cmp returns -1, 0 or +1 when d is less than, equal to or greater than d2.
+func (d Decimal) Cmp(d2 Decimal) int {
+	a, b := align(d, d2)
+	return a.val().Cmp(b.val())
+}
+
+func (d Decimal) Equal(d2 Decimal) bool              { return d.Cmp(d2) == 0 }
+func (d Decimal) LessThan(d2 Decimal) bool           { return d.Cmp(d2) < 0 }
+func (d Decimal) LessThanOrEqual(d2 Decimal) bool    { return d.Cmp(d2) <= 0 }
+func (d Decimal) GreaterThan(d2 Decimal) bool        { return d.Cmp(d2) > 0 }
+func (d Decimal) GreaterThanOrEqual(d2 Decimal) bool { return d.Cmp(d2) >= 0 }
+
+// Sign returns -1, 0 or +1 when d is negative, zero or positive.
+func (d Decimal) Sign() int {
+	return d.val().Sign()
+}
+
+func (d Decimal) IsZero() bool     { return d.Sign() == 0 }
+func (d Decimal) IsPositive() bool { return d.Sign() > 0 }
+func (d Decimal) IsNegative() bool { return d.Sign() < 0 }
+
+// Exponent returns the exponent of d, which is value * 10^exp.
+func (d Decimal) Exponent() int32 {
+	return d.exp
+}
+
+// Places returns the number of significant decimal places of d, e.g. 1 for 1.50 and 0 for 100.
+func (d Decimal) Places() int32 {
+	if d.exp >= 0 || d.IsZero() {
+		return 0
+	}
+	value, places := new(big.Int).Set(d.val()), -d.exp
+	r := new(big.Int)
+	for places > 0 {
+		q, _ := new(big.Int).QuoRem(value, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		value = q
+		places--
+	}
+	return places
+}
+
+// IntPart returns the integer part of d.
+func (d Decimal) IntPart() int64 {
+	return d.Truncate(0).rescale(0).val().Int64()
+}
+
+// Float64 returns the nearest float64 of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d without exponent and without trailing zeros, e.g. "0.00000001".
+func (d Decimal) String() string {
+	return d.format(true)
+}
+
+// StringFixed formats d rounded to exactly the given decimal places, e.g. "1.50".
+func (d Decimal) StringFixed(places int32) string {
+	rounded := d.Round(places)
+	if rounded.exp > -places {
+		rounded = rounded.rescale(-places)
+	}
+	return rounded.format(false)
+}
+
+func (d Decimal) format(trimZeros bool) string {
+	if d.exp >= 0 {
+		return d.rescale(0).val().String()
+	}
+
+	digits := new(big.Int).Abs(d.val()).String()
+	places := int(-d.exp)
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-places], digits[len(digits)-places:]
+	if trimZeros {
+		fracPart = strings.TrimRight(fracPart, "0")
+	}
+
+	str := intPart
+	if fracPart != "" {
+		str += "." + fracPart
+	}
+	if d.Sign() < 0 {
+		str = "-" + str
+	}
+	return str
+}
+
+// MarshalJSON encodes d as a JSON number without loss of precision.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a string holding a number. null and "" decode to zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if str == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	if len(str) >= 2 && str[0] == '"' && str[len(str)-1] == '"' {
+		str = str[1 : len(str)-1]
+	}
+	if str == "" {
+		*d = Decimal{}
+		return nil
+	}
+
+	parsed, err := NewFromString(str)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalText encodes d as in String.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a number as in NewFromString.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := NewFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,175 @@
+package decimal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+func TestNewFromString(t *testing.T) {
+	tests := map[string]string{
+		"0":                    "0",
+		"-0":                   "0",
+		"0.00000001":           "0.00000001",
+		"1.50":                 "1.5",
+		"+12.000":              "12",
+		"-0.5":                 "-0.5",
+		".25":                  "0.25",
+		"1e-8":                 "0.00000001",
+		"1.5E3":                "1500",
+		"123456789.123456789":  "123456789.123456789",
+		"99999999999999999999": "99999999999999999999",
+	}
+	for in, want := range tests {
+		d, err := decimal.NewFromString(in)
+		if err != nil {
+			t.Errorf("NewFromString(%q): %v", in, err)
+			continue
+		}
+		if got := d.String(); got != want {
+			t.Errorf("NewFromString(%q) = %s, want %s", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"", "-", ".", "abc", "1.2.3", "1e", "1e1.5", "0x10", " 1"} {
+		if _, err := decimal.NewFromString(in); err == nil {
+			t.Errorf("NewFromString(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestNewFromFloat(t *testing.T) {
+	tests := map[float64]string{
+		0.1:        "0.1",
+		0.00000001: "0.00000001",
+		-2.5:       "-2.5",
+		1e21:       "1000000000000000000000",
+		216415:     "216415",
+	}
+	for in, want := range tests {
+		if got := decimal.NewFromFloat(in).String(); got != want {
+			t.Errorf("NewFromFloat(%v) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	d := decimal.RequireFromString
+
+	if got := d("0.1").Add(d("0.2")); !got.Equal(d("0.3")) {
+		t.Errorf("0.1 + 0.2 = %s", got)
+	}
+	if got := d("1").Sub(d("0.00000001")); got.String() != "0.99999999" {
+		t.Errorf("1 - 0.00000001 = %s", got)
+	}
+	if got := d("0.00123").Mul(d("2150000.5")); got.String() != "2644.500615" {
+		t.Errorf("0.00123 * 2150000.5 = %s", got)
+	}
+	if got := d("1").Div(d("3")); got.String() != "0.3333333333333333" {
+		t.Errorf("1 / 3 = %s", got)
+	}
+	if got := d("2").DivRound(d("3"), 2); got.String() != "0.67" {
+		t.Errorf("2 / 3 = %s", got)
+	}
+	if got := d("-2").DivRound(d("3"), 2); got.String() != "-0.67" {
+		t.Errorf("-2 / 3 = %s", got)
+	}
+	if got := d("100").Div(d("0.25")); got.String() != "400" {
+		t.Errorf("100 / 0.25 = %s", got)
+	}
+	if got := decimal.Zero.Add(d("1.5")).Neg(); got.String() != "-1.5" {
+		t.Errorf("-(0 + 1.5) = %s", got)
+	}
+}
+
+func TestRounding(t *testing.T) {
+	d := decimal.RequireFromString
+	tests := []struct {
+		in                        string
+		places                    int32
+		round, trunc, floor, ceil string
+	}{
+		{"1.005", 2, "1.01", "1", "1", "1.01"},
+		{"1.004", 2, "1", "1", "1", "1.01"},
+		{"-1.005", 2, "-1.01", "-1", "-1.01", "-1"},
+		{"123.456", 0, "123", "123", "123", "124"},
+		{"0.123456789", 8, "0.12345679", "0.12345678", "0.12345678", "0.12345679"},
+		{"1.5", 4, "1.5", "1.5", "1.5", "1.5"},
+	}
+	for _, tt := range tests {
+		in := d(tt.in)
+		if got := in.Round(tt.places).String(); got != tt.round {
+			t.Errorf("%s.Round(%d) = %s, want %s", tt.in, tt.places, got, tt.round)
+		}
+		if got := in.Truncate(tt.places).String(); got != tt.trunc {
+			t.Errorf("%s.Truncate(%d) = %s, want %s", tt.in, tt.places, got, tt.trunc)
+		}
+		if got := in.RoundFloor(tt.places).String(); got != tt.floor {
+			t.Errorf("%s.RoundFloor(%d) = %s, want %s", tt.in, tt.places, got, tt.floor)
+		}
+		if got := in.RoundCeil(tt.places).String(); got != tt.ceil {
+			t.Errorf("%s.RoundCeil(%d) = %s, want %s", tt.in, tt.places, got, tt.ceil)
+		}
+	}
+
+	if got := d("1.5").StringFixed(2); got != "1.50" {
+		t.Errorf("StringFixed = %s", got)
+	}
+	if got := d("0.000000015").StringFixed(8); got != "0.00000002" {
+		t.Errorf("StringFixed = %s", got)
+	}
+	if got := d("1.2300").Places(); got != 2 {
+		t.Errorf("Places = %d", got)
+	}
+	if got := d("1200").Places(); got != 0 {
+		t.Errorf("Places = %d", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	d := decimal.RequireFromString
+	if !d("1.10").Equal(d("1.1")) || d("1.1").Cmp(d("1.11")) >= 0 || !d("-1").LessThan(decimal.Zero) {
+		t.Error("unexpected comparison")
+	}
+	if !decimal.Zero.IsZero() || decimal.Zero.Sign() != 0 || !d("0.0").IsZero() {
+		t.Error("zero is not zero")
+	}
+	if got := decimal.Min(d("3"), d("1.5"), d("2")); got.String() != "1.5" {
+		t.Errorf("Min = %s", got)
+	}
+	if got := decimal.Max(d("3"), d("1.5"), d("3.01")); got.String() != "3.01" {
+		t.Errorf("Max = %s", got)
+	}
+	if got := d("-12.9").IntPart(); got != -12 {
+		t.Errorf("IntPart = %d", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		Number decimal.Decimal `json:"number"`
+		String decimal.Decimal `json:"string"`
+		Null   decimal.Decimal `json:"null"`
+		Empty  decimal.Decimal `json:"empty"`
+	}
+	data := `{"number":0.12345678901234567890,"string":"2150000.55","null":null,"empty":""}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Number.String() != "0.1234567890123456789" || v.String.String() != "2150000.55" || !v.Null.IsZero() || !v.Empty.IsZero() {
+		t.Fatalf("unexpected values %+v", v)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"number":0.1234567890123456789,"string":2150000.55,"null":0,"empty":0}`; string(out) != want {
+		t.Fatalf("got %s, want %s", out, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"number":"abc"}`), &v); err == nil {
+		t.Fatal("expected error for invalid number")
+	}
+}
@@ -0,0 +1,50 @@
+package bitkub
+
+import "strings"
+
+// testEndpoints are the order endpoints with a test counterpart, which validates an order without placing it.
+var testEndpoints = map[string]bool{
+	"/api/market/place-bid": true,
+	"/api/market/place-ask": true,
+}
+
+// DryRunRequest is a request changing the account made by a client in dry run mode, see WithDryRun.
+type DryRunRequest struct {
+	Method  string
+	Path    string                 // path the request was sent to, or would have been sent to
+	Payload map[string]interface{} // payload before it was signed
+
+	// Simulated is set when the request was not sent, e.g. for CancelOrder or FiatWithdraw. The
+	// call then succeeds with a zero result, e.g. an empty model.FiatWithdraw.
+	Simulated bool
+}
+
+// DryRun reports whether the client is in dry run mode.
+func (b *Client) DryRun() bool {
+	return b.dryRun
+}
+
+// dryRunPath returns the test path of an order endpoint, or path and true when the request must be simulated.
+func dryRunPath(path, endpoint string) (string, bool) {
+	if !testEndpoints[endpoint] {
+		return path, true
+	}
+	query := ""
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path, query = path[:i], path[i:]
+	}
+	return path + "/test" + query, false
+}
+
+func (b *Client) logDryRun(request DryRunRequest) {
+	if b.dryRunLog == nil {
+		return
+	}
+	// a copy, so that the log cannot change the payload which is sent
+	payload := make(map[string]interface{}, len(request.Payload))
+	for k, v := range request.Payload {
+		payload[k] = v
+	}
+	request.Payload = payload
+	b.dryRunLog(request)
+}
@@ -0,0 +1,87 @@
+package bitkub_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+func TestDryRun(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent = append(sent, r.URL.Path)
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/api/market/balances":
+			w.Write([]byte(`{"error":0,"result":{}}`))
+			return
+		case strings.HasPrefix(r.URL.Path, "/api/v3/"):
+			w.Write([]byte(`{"error":0,"result":{"id":"0","hash":"test"}}`))
+			return
+		}
+		w.Write([]byte(`{"error":0,"result":{"id":0,"hash":"test","typ":"limit","amt":100,"rat":1000000}}`))
+	}))
+	defer srv.Close()
+
+	logged := []string{}
+	api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil),
+		bitkub.WithDryRun(func(r bitkub.DryRunRequest) {
+			logged = append(logged, fmt.Sprintf("%s %s %v sym=%v", r.Method, r.Path, r.Simulated, r.Payload["sym"]))
+		}))
+	if !api.DryRun() {
+		t.Fatal("expected dry run mode")
+	}
+
+	d := decimal.RequireFromString
+	if order, err := api.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, d("100"), d("1000000")); err != nil || order.Hash != "test" {
+		t.Fatalf("unexpected order %+v %v", order, err)
+	}
+	if _, err := api.PlaceOrder(bitkub.SellOrder("THB_BTC").Market().Amount(d("0.001")).Test()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.V3().PlaceAsk(context.Background(), "btc_thb", bitkub.OrderTypeMarket, d("0.001"), decimal.Zero); err != nil {
+		t.Fatal(err)
+	}
+	if order, err := api.PlaceAskByFiat("THB_BTC", bitkub.OrderTypeMarket, d("100"), decimal.Zero); err != nil || order.Hash != "" {
+		t.Fatalf("unexpected order %+v %v", order, err)
+	}
+	if err := api.CancelOrder("", "", "fwQ6dnQWQq71S9vZ9PNzX59MF28", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.CryptoWithdraw("BTC", "address", d("0.1"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.V3().FiatWithdraw(context.Background(), "bank", d("100")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.GetBalances(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "/api/market/place-bid/test,/api/market/place-ask/test,/api/v3/market/place-ask/test,/api/market/balances"
+	if strings.Join(sent, ",") != expected {
+		t.Fatalf("unexpected requests %v", sent)
+	}
+	expected = strings.Join([]string{
+		"POST /api/market/place-bid/test false sym=THB_BTC",
+		"POST /api/v3/market/place-ask/test false sym=btc_thb",
+		"POST /api/market/place-ask-by-fiat true sym=THB_BTC",
+		"POST /api/market/cancel-order true sym=<nil>",
+		"POST /api/crypto/withdraw true sym=<nil>",
+		"POST /api/v3/fiat/withdraw true sym=<nil>",
+	}, ",")
+	if strings.Join(logged, ",") != expected {
+		t.Fatalf("unexpected log %v", logged)
+	}
+}
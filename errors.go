@@ -0,0 +1,100 @@
+package bitkub
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/internal"
+)
+
+// APIError is returned when Bitkub answers with a non-zero error code or an unexpected HTTP status.
+type APIError struct {
+	Code       int    // Bitkub error code, 0 when the request failed at the HTTP level
+	HTTPStatus int    // HTTP status code of the response
+	Message    string // description of Code, or the HTTP status text
+	Endpoint   string // path of the endpoint, e.g. /api/market/place-bid
+	Body       string // raw response body
+	Attempts   int    // number of attempts made, including retries
+
+	RetryAfter time.Duration // wait requested by the Retry-After header of an HTTP 429 response
+}
+
+// Sentinels for common Bitkub error codes, to be used with errors.Is.
+var (
+	ErrInvalidSignature      = newCodeError(6)
+	ErrInvalidTimestamp      = newCodeError(8)
+	ErrAmountTooLow          = newCodeError(15)
+	ErrInsufficientBalance   = newCodeError(18)
+	ErrLimitExceeds          = newCodeError(30)
+	ErrWithdrawalMaintenance = newCodeError(51)
+	ErrServerError           = newCodeError(90)
+)
+
+func newCodeError(code int) *APIError {
+	return &APIError{Code: code, Message: internal.GetErrorMessage(code)}
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%s: got server error (%d) : %s", e.Endpoint, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s: got server error (%d) : %s", e.Endpoint, e.HTTPStatus, e.Body)
+}
+
+// Is reports whether target is an *APIError with the same Bitkub error code,
+// or, when the code of target is 0, the same HTTP status.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	if t.Code != 0 {
+		return t.Code == e.Code
+	}
+	return t.HTTPStatus != 0 && t.HTTPStatus == e.HTTPStatus
+}
+
+// Retryable reports whether the same request may succeed when it is sent again.
+// It does not take into account whether sending the request twice is safe.
+func (e *APIError) Retryable() bool {
+	switch e.Code {
+	case 0:
+		return e.HTTPStatus == http.StatusTooManyRequests || e.HTTPStatus >= 500
+	case 7, 8: // missing / invalid timestamp: the payload is signed again
+		return true
+	case 16, 90: // failed to get balance, server error
+		return true
+	}
+	return false
+}
+
+// DecodeError is returned when a successful response does not have the expected shape.
+type DecodeError struct {
+	Endpoint string // path of the endpoint, e.g. /api/market/trades
+	Body     string // raw response body
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s: cannot decode response: %v", e.Endpoint, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// AbandonedError is returned when the context of a request sent with fasthttp is done before the
+// response arrived. fasthttp cannot abort the request, so Bitkub may still execute it: an order or
+// a withdrawal failing with it must be looked up before being sent again. It wraps the context error.
+type AbandonedError = internal.AbandonedError
+
+// StreamAuthError is returned when the private stream rejected the token.
+type StreamAuthError struct {
+	Code    string
+	Message string
+}
+
+func (e *StreamAuthError) Error() string {
+	return fmt.Sprintf("stream authentication failed (%s) %s", e.Code, e.Message)
+}
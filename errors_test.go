@@ -0,0 +1,83 @@
+package bitkub_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+func TestAPIErrorCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":18,"result":{}}`))
+	}))
+	defer srv.Close()
+
+	api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL))
+	_, err := api.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, decimal.NewFromInt(100), decimal.NewFromInt(1000000))
+	if !errors.Is(err, bitkub.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+	if errors.Is(err, bitkub.ErrAmountTooLow) {
+		t.Fatal("error must not match a different code")
+	}
+
+	var apiErr *bitkub.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.Code != 18 || apiErr.HTTPStatus != 200 || apiErr.Endpoint != "/api/market/place-bid" {
+		t.Fatalf("unexpected error %+v", apiErr)
+	}
+	if apiErr.Retryable() {
+		t.Fatal("insufficient balance must not be retryable")
+	}
+}
+
+func TestAPIErrorHTTPStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("bad gateway"))
+	}))
+	defer srv.Close()
+
+	api := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL))
+	_, err := api.GetMarketSymbols()
+
+	var apiErr *bitkub.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.Code != 0 || apiErr.HTTPStatus != http.StatusBadGateway || apiErr.Body != "bad gateway" {
+		t.Fatalf("unexpected error %+v", apiErr)
+	}
+	if !apiErr.Retryable() {
+		t.Fatal("5xx must be retryable")
+	}
+	if !errors.Is(err, &bitkub.APIError{HTTPStatus: http.StatusBadGateway}) {
+		t.Fatal("expected match on HTTP status")
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"error":0,"result":[[1529516287,null]]}`))
+	}))
+	defer srv.Close()
+
+	api := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL))
+	trades, err := api.GetMarketTrades("THB_BTC", 10)
+
+	var decodeErr *bitkub.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %v", err)
+	}
+	if trades != nil || decodeErr.Endpoint != "/api/market/trades" || calls != 1 {
+		t.Fatalf("unexpected error %+v after %d calls", decodeErr, calls)
+	}
+}
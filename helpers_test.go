@@ -0,0 +1,52 @@
+package bitkub_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go"
+)
+
+// marketSymbolsJSON and symbolInfoJSON are the symbols of BTC, a frozen ETH and NEW without rules.
+const (
+	marketSymbolsJSON = `{"error":0,"result":[{"id":1,"symbol":"THB_BTC","info":"Thai Baht to Bitcoin"},{"id":2,"symbol":"THB_ETH","info":"Thai Baht to Ethereum"},{"id":3,"symbol":"THB_NEW","info":"Thai Baht to New"}]}`
+	symbolInfoJSON    = `{"error":0,"result":[
+		{"symbol":"BTC_THB","base_asset":"BTC","base_asset_scale":8,"quote_asset":"THB","quote_asset_scale":2,"pairing_id":1,"price_scale":2,"min_quote_size":10,"status":"active"},
+		{"symbol":"ETH_THB","base_asset":"ETH","base_asset_scale":8,"quote_asset":"THB","quote_asset_scale":2,"pairing_id":2,"price_scale":2,"min_quote_size":10,"status":"active","freeze_buy":true}]}`
+)
+
+// testClient serves handler and returns a client of it without client-side rate limiting, with opts.
+func testClient(t *testing.T, handler http.Handler, opts ...bitkub.Option) *bitkub.Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	opts = append([]bitkub.Option{bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil)}, opts...)
+	return bitkub.NewClient("key", "secret", opts...)
+}
+
+// symbolMux serves marketSymbolsJSON and symbolInfoJSON, counting the loads of the former in loads
+// unless it is nil.
+func symbolMux(loads *int32) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/market/symbols", func(w http.ResponseWriter, r *http.Request) {
+		if loads != nil {
+			atomic.AddInt32(loads, 1)
+		}
+		w.Write([]byte(marketSymbolsJSON))
+	})
+	mux.HandleFunc("/api/v3/market/symbols", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(symbolInfoJSON))
+	})
+	return mux
+}
+
+// payloadOf decodes the JSON body of r, empty when it has none.
+func payloadOf(r *http.Request) map[string]interface{} {
+	body, _ := io.ReadAll(r.Body)
+	payload := map[string]interface{}{}
+	json.Unmarshal(body, &payload)
+	return payload
+}
@@ -1,97 +1,206 @@
 package internal
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"time"
 
 	"github.com/valyala/fasthttp"
 )
 
-func Get(url string, headers map[string]string, timeout ...time.Duration) (*fasthttp.Response, error) {
-	req := fasthttp.AcquireRequest()
-	resp := fasthttp.AcquireResponse()
-	defer func() {
-		fasthttp.ReleaseResponse(resp)
-		fasthttp.ReleaseRequest(req)
-	}()
+// Request is a transport independent HTTP request.
+type Request struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
 
-	req.Header.SetMethod("GET")
-	if err := doRequest(req, resp, url, headers, timeout...); err != nil {
-		return nil, err
-	}
+// Response is a transport independent HTTP response. The body is fully read.
+type Response struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
 
-	out := fasthttp.AcquireResponse()
-	resp.CopyTo(out)
-	return out, nil
+func (r *Response) StatusCode() int {
+	return r.statusCode
 }
 
-func Post(url string, headers map[string]string, payload []byte, timeout ...time.Duration) (*fasthttp.Response, error) {
-	req := fasthttp.AcquireRequest()
-	resp := fasthttp.AcquireResponse()
-	defer func() {
-		fasthttp.ReleaseResponse(resp)
-		fasthttp.ReleaseRequest(req)
-	}()
+func (r *Response) Body() []byte {
+	return r.body
+}
 
-	req.Header.SetMethod("POST")
-	req.SetBody(payload)
-	if err := doRequest(req, resp, url, headers, timeout...); err != nil {
-		return nil, err
-	}
+func (r *Response) String() string {
+	return string(r.body)
+}
 
-	out := fasthttp.AcquireResponse()
-	resp.CopyTo(out)
-	return out, nil
+// Header returns the first value of the given response header.
+func (r *Response) Header(key string) string {
+	return r.header.Get(key)
 }
 
-func Put(url string, headers map[string]string, payload []byte, timeout ...time.Duration) (*fasthttp.Response, error) {
+// Transport sends a Request and returns its Response. Implementations must return as soon as ctx is done.
+type Transport interface {
+	Do(ctx context.Context, req *Request) (*Response, error)
+}
+
+// FastHTTPDoer is implemented by fasthttp.Client and fasthttp.HostClient.
+type FastHTTPDoer interface {
+	DoDeadline(req *fasthttp.Request, resp *fasthttp.Response, deadline time.Time) error
+}
+
+type defaultDoer struct{}
+
+func (defaultDoer) DoDeadline(req *fasthttp.Request, resp *fasthttp.Response, deadline time.Time) error {
+	return fasthttp.DoDeadline(req, resp, deadline)
+}
+
+// FastHTTPTransport sends requests with fasthttp. A nil Client uses the fasthttp default client.
+//
+// fasthttp cannot abort a request in flight: when ctx is done first, Do returns an *AbandonedError
+// while the request goes on until its deadline, so a request changing the account may still be
+// executed after the caller was told it failed.
+type FastHTTPTransport struct {
+	Client FastHTTPDoer
+}
+
+// Do sends the request and waits for the response or ctx, whichever comes first.
+// Requests without a ctx deadline use a 10 seconds timeout.
+// Once the request was handed to fasthttp, a done ctx yields an *AbandonedError wrapping ctx.Err().
+func (t *FastHTTPTransport) Do(ctx context.Context, r *Request) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &NotSentError{Err: err}
+	}
+
+	client := t.Client
+	if client == nil {
+		client = defaultDoer{}
+	}
+
 	req := fasthttp.AcquireRequest()
 	resp := fasthttp.AcquireResponse()
-	defer func() {
-		fasthttp.ReleaseResponse(resp)
-		fasthttp.ReleaseRequest(req)
+
+	req.Header.SetMethod(r.Method)
+	req.SetRequestURI(r.URL)
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+	if r.Body != nil {
+		req.SetBody(r.Body)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.DoDeadline(req, resp, deadline)
 	}()
 
-	req.Header.SetMethod("PUT")
-	req.SetBody(payload)
-	if err := doRequest(req, resp, url, headers, timeout...); err != nil {
-		return nil, err
+	select {
+	case err := <-done:
+		defer func() {
+			fasthttp.ReleaseRequest(req)
+			fasthttp.ReleaseResponse(resp)
+		}()
+		if err != nil {
+			return nil, notSent(err)
+		}
+		out := &Response{
+			statusCode: resp.StatusCode(),
+			header:     http.Header{},
+			body:       append([]byte(nil), resp.Body()...),
+		}
+		resp.Header.VisitAll(func(key, value []byte) {
+			out.header.Add(string(key), string(value))
+		})
+		return out, nil
+	case <-ctx.Done():
+		// fasthttp cannot interrupt a call in flight, so hand the request back to
+		// the pool once it gives up instead of blocking the caller until then.
+		go func() {
+			<-done
+			fasthttp.ReleaseRequest(req)
+			fasthttp.ReleaseResponse(resp)
+		}()
+		return nil, &AbandonedError{Err: ctx.Err()}
 	}
+}
 
-	out := fasthttp.AcquireResponse()
-	resp.CopyTo(out)
-	return out, nil
+// AbandonedError is returned when ctx is done while a request which cannot be aborted is in flight.
+// The server may still receive and execute the request. It wraps the error of ctx.
+type AbandonedError struct {
+	Err error
 }
 
-func Delete(url string, headers map[string]string, timeout ...time.Duration) (*fasthttp.Response, error) {
-	req := fasthttp.AcquireRequest()
-	resp := fasthttp.AcquireResponse()
-	defer func() {
-		fasthttp.ReleaseResponse(resp)
-		fasthttp.ReleaseRequest(req)
-	}()
+func (e *AbandonedError) Error() string {
+	return fmt.Sprintf("request abandoned in flight, it may still be executed: %v", e.Err)
+}
 
-	req.Header.SetMethod("DELETE")
-	if err := doRequest(req, resp, url, headers, timeout...); err != nil {
-		return nil, err
+func (e *AbandonedError) Unwrap() error {
+	return e.Err
+}
+
+// NotSentError marks an error which occurred before the request was written, so that the server
+// cannot have received it. It has the message of the error it wraps.
+type NotSentError struct {
+	Err error
+}
+
+func (e *NotSentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NotSentError) Unwrap() error {
+	return e.Err
+}
+
+// notSent wraps err in a *NotSentError when no connection could be made to send the request.
+func notSent(err error) error {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" ||
+		errors.Is(err, fasthttp.ErrDialTimeout) || errors.Is(err, fasthttp.ErrNoFreeConns) {
+		return &NotSentError{Err: err}
 	}
+	return err
+}
 
-	out := fasthttp.AcquireResponse()
-	resp.CopyTo(out)
-	return out, nil
+// HTTPTransport sends requests with a net/http RoundTripper. A nil RoundTripper uses http.DefaultTransport.
+type HTTPTransport struct {
+	RoundTripper http.RoundTripper
 }
 
-func doRequest(req *fasthttp.Request, resp *fasthttp.Response, url string, headers map[string]string, timeout ...time.Duration) error {
-	req.SetRequestURI(url)
-	req.Header.Add("User-Agent", "bitkub-go/1.0")
-	for k, v := range headers {
-		req.Header.Add(k, v)
+func (t *HTTPTransport) Do(ctx context.Context, r *Request) (*Response, error) {
+	rt := t.RoundTripper
+	if rt == nil {
+		rt = http.DefaultTransport
 	}
-	timeOut := 10 * time.Second
-	if len(timeout) > 0 {
-		timeOut = timeout[0]
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, &NotSentError{Err: err}
+	}
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, notSent(err)
 	}
-	if err := fasthttp.DoTimeout(req, resp, timeOut); err != nil {
-		return err
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &Response{statusCode: resp.StatusCode, header: resp.Header, body: body}, nil
 }
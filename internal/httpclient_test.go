@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func hangingServer(t *testing.T) *httptest.Server {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(func() {
+		close(release)
+		srv.Close()
+	})
+	return srv
+}
+
+func TestTransportCancel(t *testing.T) {
+	for name, transport := range map[string]Transport{
+		"fasthttp": &FastHTTPTransport{},
+		"net/http": &HTTPTransport{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := hangingServer(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			start := time.Now()
+			_, err := transport.Do(ctx, &Request{Method: "GET", URL: srv.URL})
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("request was not aborted, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestTransportDeadline(t *testing.T) {
+	for name, transport := range map[string]Transport{
+		"fasthttp": &FastHTTPTransport{},
+		"net/http": &HTTPTransport{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := hangingServer(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			if _, err := transport.Do(ctx, &Request{Method: "GET", URL: srv.URL}); err == nil {
+				t.Fatal("expected an error")
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("deadline of ctx was not honored, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestFastHTTPTransportAbandoned(t *testing.T) {
+	srv := hangingServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := (&FastHTTPTransport{}).Do(ctx, &Request{Method: "POST", URL: srv.URL})
+	var abandoned *AbandonedError
+	if !errors.As(err, &abandoned) {
+		t.Fatalf("expected an *AbandonedError, got %v", err)
+	}
+}
@@ -1,6 +0,0 @@
-package model
-
-type Balance struct {
-	Available float64 `json:"available"`
-	Reserved  float64 `json:"reserved"`
-}
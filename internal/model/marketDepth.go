@@ -1,6 +0,0 @@
-package model
-
-type MarketDepth struct {
-	Price  float64 `json:"price"`
-	Volumn float64 `json:"volumn"`
-}
@@ -1,5 +0,0 @@
-package model
-
-type MarketWallet struct {
-	THB float64 `json:"thb"`
-}
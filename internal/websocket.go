@@ -0,0 +1,370 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes, see RFC 6455 section 5.2.
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xa
+)
+
+const (
+	wsGUID           = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsMaxMessageSize = 16 << 20
+)
+
+// CloseError is returned by ReadMessage when the peer closed the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket closed (%d) %s", e.Code, e.Reason)
+}
+
+// WSConn is a WebSocket connection. ReadMessage must be called from a single goroutine,
+// the other methods are safe for concurrent use.
+type WSConn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool // client frames are masked
+
+	wmu    sync.Mutex
+	closed bool
+
+	// OnPong is called from ReadMessage with the payload of each pong.
+	OnPong func(data []byte)
+}
+
+// DialWebSocket opens a WebSocket connection to a ws:// or wss:// URL.
+func DialWebSocket(ctx context.Context, rawURL string, headers map[string]string) (*WSConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	switch u.Scheme {
+	case "ws":
+		if u.Port() == "" {
+			host += ":80"
+		}
+	case "wss":
+		if u.Port() == "" {
+			host += ":443"
+		}
+	default:
+		return nil, fmt.Errorf("websocket: unsupported scheme %q", u.Scheme)
+	}
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+
+	if u.Scheme == "wss" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, contextError(ctx, err)
+		}
+		conn = tlsConn
+	}
+
+	ws, err := handshake(conn, u, headers)
+	if err != nil {
+		conn.Close()
+		return nil, contextError(ctx, err)
+	}
+	conn.SetDeadline(time.Time{})
+	return ws, nil
+}
+
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func handshake(conn net.Conn, u *url.URL, headers map[string]string) (*WSConn, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     "GET",
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Host:       u.Host,
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("websocket: handshake failed with status %s", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, errors.New("websocket: invalid Sec-WebSocket-Accept")
+	}
+	return &WSConn{conn: conn, br: br, client: true}, nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// UpgradeWebSocket answers a WebSocket handshake on the server side, e.g. for a local test server.
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request) (*WSConn, error) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || r.Header.Get("Sec-WebSocket-Key") == "" {
+		http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+		return nil, errors.New("websocket: not a websocket handshake")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("websocket: response does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(resp)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &WSConn{conn: conn, br: rw.Reader}, nil
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered and pongs are passed
+// to OnPong while waiting. A close frame is answered and returned as a *CloseError.
+func (c *WSConn) ReadMessage() (int, []byte, error) {
+	var (
+		op      int
+		message []byte
+	)
+	for {
+		fin, frameOp, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOp {
+		case OpPing:
+			if err := c.WriteMessage(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			if c.OnPong != nil {
+				c.OnPong(payload)
+			}
+			continue
+		case OpClose:
+			closeErr := &CloseError{Code: 1005}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			c.writeClose(payload)
+			c.conn.Close()
+			return 0, nil, closeErr
+		case OpContinuation:
+			if op == 0 {
+				return 0, nil, errors.New("websocket: unexpected continuation frame")
+			}
+		case OpText, OpBinary:
+			if op != 0 {
+				return 0, nil, errors.New("websocket: expected continuation frame")
+			}
+			op = frameOp
+		default:
+			return 0, nil, fmt.Errorf("websocket: unknown opcode %d", frameOp)
+		}
+
+		if len(message)+len(payload) > wsMaxMessageSize {
+			return 0, nil, errors.New("websocket: message too large")
+		}
+		message = append(message, payload...)
+		if fin {
+			return op, message, nil
+		}
+	}
+}
+
+func (c *WSConn) readFrame() (bool, int, []byte, error) {
+	header := make([]byte, 2, 8)
+	if _, err := io.ReadFull(c.br, header); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	op := int(header[0] & 0x0f)
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		if _, err := io.ReadFull(c.br, header[:2]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(header[:2]))
+	case 127:
+		header = header[:8]
+		if _, err := io.ReadFull(c.br, header); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(header)
+	}
+	if length > wsMaxMessageSize {
+		return false, 0, nil, errors.New("websocket: frame too large")
+	}
+	if op >= OpClose && (length > 125 || !fin) {
+		return false, 0, nil, errors.New("websocket: invalid control frame")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// WriteMessage sends data in a single frame.
+func (c *WSConn) WriteMessage(op int, data []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	return c.writeFrame(op, data)
+}
+
+func (c *WSConn) writeFrame(op int, data []byte) error {
+	frame := make([]byte, 0, len(data)+14)
+	frame = append(frame, 0x80|byte(op))
+
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	switch {
+	case len(data) < 126:
+		frame = append(frame, maskBit|byte(len(data)))
+	case len(data) <= 0xffff:
+		frame = append(frame, maskBit|126, byte(len(data)>>8), byte(len(data)))
+	default:
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+		frame = append(append(frame, maskBit|127), length[:]...)
+	}
+
+	if !c.client {
+		frame = append(frame, data...)
+		_, err := c.conn.Write(frame)
+		return err
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range data {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+func (c *WSConn) writeClose(payload []byte) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.writeFrame(OpClose, payload)
+}
+
+// SetReadDeadline sets the deadline of ReadMessage.
+func (c *WSConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// Close sends a normal close frame and closes the connection.
+func (c *WSConn) Close() error {
+	c.writeClose([]byte{0x03, 0xe8}) // 1000 normal closure
+	return c.conn.Close()
+}
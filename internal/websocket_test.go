@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func wsServer(t *testing.T, handler func(conn *WSConn)) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := UpgradeWebSocket(w, r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		handler(conn)
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestWebSocketMessages(t *testing.T) {
+	pong := make(chan string, 1)
+	url := wsServer(t, func(conn *WSConn) {
+		conn.OnPong = func(data []byte) { pong <- string(data) }
+
+		// echo the first message, then reply with a fragmented message around a ping
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.WriteMessage(OpText, data)
+		conn.WriteMessage(OpText, []byte(strings.Repeat("a", 200)))
+		conn.WriteMessage(OpPing, []byte("ping"))
+		conn.WriteMessage(OpText, []byte("after ping"))
+		conn.ReadMessage() // wait for the pong and the close of the client
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := DialWebSocket(ctx, url, map[string]string{"User-Agent": "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	large := strings.Repeat("x", 70000)
+	if err := conn.WriteMessage(OpText, []byte(large)); err != nil {
+		t.Fatal(err)
+	}
+	if op, data, err := conn.ReadMessage(); err != nil || op != OpText || string(data) != large {
+		t.Fatalf("unexpected echo: op %d, %d bytes, %v", op, len(data), err)
+	}
+	if _, data, err := conn.ReadMessage(); err != nil || len(data) != 200 {
+		t.Fatalf("unexpected message: %d bytes, %v", len(data), err)
+	}
+
+	if _, data, err := conn.ReadMessage(); err != nil || string(data) != "after ping" {
+		t.Fatalf("unexpected message %q, %v", data, err)
+	}
+	select {
+	case data := <-pong:
+		if data != "ping" {
+			t.Fatalf("unexpected pong %q", data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ping was not answered")
+	}
+}
+
+func TestWebSocketClose(t *testing.T) {
+	url := wsServer(t, func(conn *WSConn) {
+		conn.Close()
+	})
+
+	conn, err := DialWebSocket(context.Background(), url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var closeErr *CloseError
+	if _, _, err := conn.ReadMessage(); !errors.As(err, &closeErr) || closeErr.Code != 1000 {
+		t.Fatalf("expected close 1000, got %v", err)
+	}
+}
+
+func TestWebSocketFragments(t *testing.T) {
+	url := wsServer(t, func(conn *WSConn) {
+		conn.wmu.Lock()
+		conn.conn.Write([]byte{OpText, 3, 'f', 'o', 'o'})                // first fragment, fin unset
+		conn.conn.Write([]byte{0x80 | OpPing, 1, 'p'})                   // control frame in between
+		conn.conn.Write([]byte{0x80 | OpContinuation, 3, 'b', 'a', 'r'}) // last fragment
+		conn.wmu.Unlock()
+		conn.ReadMessage() // pong is consumed by OnPong, wait for close
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := DialWebSocket(ctx, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, data, err := conn.ReadMessage(); err != nil || string(data) != "foobar" {
+		t.Fatalf("unexpected message %q, %v", data, err)
+	}
+}
+
+func TestWebSocketHandshakeFailure(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := DialWebSocket(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected handshake error, got %v", err)
+	}
+}
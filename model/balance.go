@@ -0,0 +1,13 @@
+package model
+
+import "github.com/ChanasinP/bitkub-go/decimal"
+
+type Balance struct {
+	Available decimal.Decimal `json:"available"`
+	Reserved  decimal.Decimal `json:"reserved"`
+}
+
+type BalancesResponse struct {
+	Error  int                `json:"error"`
+	Result map[string]Balance `json:"result"`
+}
@@ -0,0 +1,56 @@
+// Package model contains the request and response types of the Bitkub API.
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Pagination struct {
+	Page int `json:"page"`
+	Last int `json:"last"`
+}
+
+type ErrorResponse struct {
+	Error int `json:"error"`
+}
+
+type DefaultResponse struct {
+	Error  int         `json:"error"`
+	Result interface{} `json:"result"`
+}
+
+// arrayField is a struct field decoded from a positional JSON array.
+type arrayField struct {
+	name  string
+	value interface{}
+}
+
+// unmarshalArray decodes a positional JSON array such as [1529516287, 10000.00, 0.09975000, "BUY"]
+// into fields, in order. Extra elements are ignored and null leaves the fields untouched.
+func unmarshalArray(data []byte, typ string, fields ...arrayField) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("decode %s: expected an array, got %s", typ, truncate(data))
+	}
+	if len(items) < len(fields) {
+		return fmt.Errorf("decode %s: expected %d elements, got %d", typ, len(fields), len(items))
+	}
+	for i, field := range fields {
+		if err := json.Unmarshal(items[i], field.value); err != nil {
+			return fmt.Errorf("decode %s: element %d (%s): %w", typ, i, field.name, err)
+		}
+	}
+	return nil
+}
+
+func truncate(data []byte) string {
+	if len(data) > 32 {
+		return string(data[:32]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+// Statuses of TradingViewHistory.
+const (
+	HistoryStatusOK     = "ok"
+	HistoryStatusNoData = "no_data"
+	HistoryStatusError  = "error"
+)
+
+// Candle is an OHLCV bar starting at Time.
+type Candle struct {
+	Time   time.Time
+	Open   decimal.Decimal
+	High   decimal.Decimal
+	Low    decimal.Decimal
+	Close  decimal.Decimal
+	Volume decimal.Decimal
+}
+
+// TradingViewHistory is the response of /tradingview/history, with the bars as parallel arrays.
+type TradingViewHistory struct {
+	Status   string            `json:"s"`        // ok, no_data or error
+	Message  string            `json:"errmsg"`   // reason of an error
+	NextTime int64             `json:"nextTime"` // time of the previous bar when there is no data in the range
+	Time     []int64           `json:"t"`        // start of the bars
+	Open     []decimal.Decimal `json:"o"`
+	High     []decimal.Decimal `json:"h"`
+	Low      []decimal.Decimal `json:"l"`
+	Close    []decimal.Decimal `json:"c"`
+	Volume   []decimal.Decimal `json:"v"`
+}
+
+// Candles returns the bars, none when the status is no_data.
+func (h *TradingViewHistory) Candles() ([]Candle, error) {
+	switch h.Status {
+	case HistoryStatusOK:
+	case HistoryStatusNoData:
+		return []Candle{}, nil
+	default:
+		return nil, fmt.Errorf("tradingview history: status %q %s", h.Status, h.Message)
+	}
+
+	n := len(h.Time)
+	for name, values := range map[string]int{"o": len(h.Open), "h": len(h.High), "l": len(h.Low), "c": len(h.Close), "v": len(h.Volume)} {
+		if values != n {
+			return nil, fmt.Errorf("tradingview history: %d times but %d values in %s", n, values, name)
+		}
+	}
+
+	candles := make([]Candle, n)
+	for i := range candles {
+		candles[i] = Candle{
+			Time:   time.Unix(h.Time[i], 0).UTC(),
+			Open:   h.Open[i],
+			High:   h.High[i],
+			Low:    h.Low[i],
+			Close:  h.Close[i],
+			Volume: h.Volume[i],
+		}
+	}
+	return candles, nil
+}
@@ -1,5 +1,7 @@
 package model
 
+import "github.com/ChanasinP/bitkub-go/decimal"
+
 type CryptoAddress struct {
 	Currency  string `json:"currency"`
 	Address   string `json:"address"`
@@ -14,13 +16,13 @@ type CryptoAddressResponse struct {
 }
 
 type CryptoWithdraw struct {
-	TxnID     string  `json:"txn"` // local transaction id
-	Address   string  `json:"adr"` // address
-	Memo      string  `json:"mem"` // memo
-	Currency  string  `json:"cur"` // currency
-	Amount    float64 `json:"amt"` // withdraw amount
-	Fee       float64 `json:"fee"` // withdraw fee
-	Timestamp int64   `json:"ts"`  // timestamp
+	TxnID     string          `json:"txn"` // local transaction id
+	Address   string          `json:"adr"` // address
+	Memo      string          `json:"mem"` // memo
+	Currency  string          `json:"cur"` // currency
+	Amount    decimal.Decimal `json:"amt"` // withdraw amount
+	Fee       decimal.Decimal `json:"fee"` // withdraw fee
+	Timestamp int64           `json:"ts"`  // timestamp
 }
 
 type CryptoWithdrawResponse struct {
@@ -35,14 +37,14 @@ type CryptoWithdrawHistoryResponse struct {
 }
 
 type CryptoDeposit struct {
-	Hash          string  `json:"hash"`     // transaction hash
-	Currency      string  `json:"currency"` // currency
-	Amount        float64 `json:"amount"`   // amount
-	FromAddress   string  `json:"from_address"`
-	ToAddress     string  `json:"to_address"`
-	Confirmations int     `json:"confirmations"`
-	Status        string  `json:"status"`
-	Timestamp     int64   `json:"time"`
+	Hash          string          `json:"hash"`     // transaction hash
+	Currency      string          `json:"currency"` // currency
+	Amount        decimal.Decimal `json:"amount"`   // amount
+	FromAddress   string          `json:"from_address"`
+	ToAddress     string          `json:"to_address"`
+	Confirmations int             `json:"confirmations"`
+	Status        string          `json:"status"`
+	Timestamp     int64           `json:"time"`
 }
 
 type CryptoDepositResponse struct {
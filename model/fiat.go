@@ -1,5 +1,7 @@
 package model
 
+import "github.com/ChanasinP/bitkub-go/decimal"
+
 type BankAccount struct {
 	ID        string `json:"id"`
 	Bank      string `json:"bank"`
@@ -8,21 +10,21 @@ type BankAccount struct {
 }
 
 type FiatWithdraw struct {
-	TxnID     string  `json:"txn"` // local transaction id
-	AccountID string  `json:"acc"` // bank account id
-	Currency  string  `json:"cur"` // currency
-	Amount    float64 `json:"amt"` // withdraw amount
-	Fee       float64 `json:"fee"` // withdraw fee
-	Receive   float64 `json:"rec"` // amount to receive
-	Timestamp int64   `json:"ts"`  // timestamp
+	TxnID     string          `json:"txn"` // local transaction id
+	AccountID string          `json:"acc"` // bank account id
+	Currency  string          `json:"cur"` // currency
+	Amount    decimal.Decimal `json:"amt"` // withdraw amount
+	Fee       decimal.Decimal `json:"fee"` // withdraw fee
+	Receive   decimal.Decimal `json:"rec"` // amount to receive
+	Timestamp int64           `json:"ts"`  // timestamp
 }
 
 type FiatDeposit struct {
-	TxnID     string  `json:"txn_id"`   // local transaction id
-	Currency  string  `json:"currency"` // currency
-	Amount    float64 `json:"amount"`   // deposit amount
-	Status    string  `json:"status"`
-	Timestamp int64   `json:"time"` // timestamp
+	TxnID     string          `json:"txn_id"`   // local transaction id
+	Currency  string          `json:"currency"` // currency
+	Amount    decimal.Decimal `json:"amount"`   // deposit amount
+	Status    string          `json:"status"`
+	Timestamp int64           `json:"time"` // timestamp
 }
 
 type FiatAccountsResponse struct {
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+type MarketDepth struct {
+	Price  decimal.Decimal `json:"price"`
+	Volumn decimal.Decimal `json:"volumn"`
+}
+
+// UnmarshalJSON decodes a price level given as [price, volume], or as a JSON object.
+func (d *MarketDepth) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '{' {
+		type marketDepth MarketDepth
+		return json.Unmarshal(data, (*marketDepth)(d))
+	}
+	return unmarshalArray(data, "market depth",
+		arrayField{"price", &d.Price},
+		arrayField{"volume", &d.Volumn},
+	)
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+type MarketBidAndAsk struct {
+	OrderID   int64           `json:"order_id"`
+	Timestamp int             `json:"timestamp"`
+	Volumn    decimal.Decimal `json:"volumn"`
+	Rate      decimal.Decimal `json:"rate"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// UnmarshalJSON decodes an order given as [order id, timestamp, volume, rate, amount], or as a JSON object.
+func (o *MarketBidAndAsk) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '{' {
+		type marketBidAndAsk MarketBidAndAsk
+		return json.Unmarshal(data, (*marketBidAndAsk)(o))
+	}
+	return unmarshalArray(data, "market order",
+		arrayField{"order id", &o.OrderID},
+		arrayField{"timestamp", &o.Timestamp},
+		arrayField{"volume", &o.Volumn},
+		arrayField{"rate", &o.Rate},
+		arrayField{"amount", &o.Amount},
+	)
+}
+
+type MarketBidAndAskResponse struct {
+	Error  int               `json:"error"`
+	Result []MarketBidAndAsk `json:"result"`
+}
+
+type MarketBooksResponse struct {
+	Error  int                          `json:"error"`
+	Result map[string][]MarketBidAndAsk `json:"result"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "github.com/ChanasinP/bitkub-go/decimal"
+
+type MarketSymbol struct {
+	ID     int    `json:"id"`
+	Symbol string `json:"symbol"`
+	Info   string `json:"info"`
+}
+
+type MarketSymbolResponse struct {
+	Error  int            `json:"error"`
+	Result []MarketSymbol `json:"result"`
+}
+
+// SymbolInfo is a symbol of /api/v3/market/symbols, with its trading rules.
+type SymbolInfo struct {
+	Symbol          string          `json:"symbol"` // e.g. BTC_THB
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	BaseAsset       string          `json:"base_asset"`
+	BaseAssetScale  int32           `json:"base_asset_scale"` // decimal places of amounts of the base asset
+	QuoteAsset      string          `json:"quote_asset"`
+	QuoteAssetScale int32           `json:"quote_asset_scale"` // decimal places of amounts of the quote asset
+	PairingID       int             `json:"pairing_id"`
+	PriceScale      int32           `json:"price_scale"` // decimal places of rates
+	PriceStep       decimal.Decimal `json:"price_step"`
+	QuantityStep    decimal.Decimal `json:"quantity_step"`
+	MinQuoteSize    decimal.Decimal `json:"min_quote_size"` // minimum value of an order in the quote asset
+	Status          string          `json:"status"`         // active when trading is open
+	FreezeBuy       bool            `json:"freeze_buy"`
+	FreezeSell      bool            `json:"freeze_sell"`
+}
+
+type SymbolInfoResponse struct {
+	Error  int          `json:"error"`
+	Result []SymbolInfo `json:"result"`
+}
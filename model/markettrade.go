@@ -0,0 +1,34 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+type MarketTrade struct {
+	Timestamp int             `json:"timestamp"`
+	Rate      decimal.Decimal `json:"rate"`
+	Amount    decimal.Decimal `json:"amount"`
+	Side      string          `json:"side"`
+}
+
+// UnmarshalJSON decodes a trade given as [timestamp, rate, amount, side], or as a JSON object.
+func (t *MarketTrade) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '{' {
+		type marketTrade MarketTrade
+		return json.Unmarshal(data, (*marketTrade)(t))
+	}
+	return unmarshalArray(data, "market trade",
+		arrayField{"timestamp", &t.Timestamp},
+		arrayField{"rate", &t.Rate},
+		arrayField{"amount", &t.Amount},
+		arrayField{"side", &t.Side},
+	)
+}
+
+type MarketTradesResponse struct {
+	Error  int           `json:"error"`
+	Result []MarketTrade `json:"result"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+// Deprecated: MarketWallet only holds THB, use Wallet.
+type MarketWallet struct {
+	THB decimal.Decimal `json:"thb"`
+}
+
+// Wallet is the available balance by currency. Currencies are upper case, e.g. THB or BTC, like
+// the keys of the balances of GetBalances.
+type Wallet map[string]decimal.Decimal
+
+func (w *Wallet) UnmarshalJSON(data []byte) error {
+	raw := map[string]decimal.Decimal{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	wallet := make(Wallet, len(raw))
+	for currency, amount := range raw {
+		wallet[strings.ToUpper(currency)] = amount
+	}
+	*w = wallet
+	return nil
+}
+
+// Get returns the balance of currency in any case, zero for a currency missing from the wallet.
+func (w Wallet) Get(currency string) decimal.Decimal {
+	amount, ok := w[strings.ToUpper(currency)]
+	if !ok {
+		return decimal.Zero
+	}
+	return amount
+}
+
+// NonZero returns the wallet without the currencies of zero balance.
+func (w Wallet) NonZero() Wallet {
+	wallet := Wallet{}
+	for currency, amount := range w {
+		if !amount.IsZero() {
+			wallet[currency] = amount
+		}
+	}
+	return wallet
+}
+
+// Currencies returns the currencies of the wallet, sorted.
+func (w Wallet) Currencies() []string {
+	currencies := make([]string, 0, len(w))
+	for currency := range w {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+type WalletResponse struct {
+	Error  int    `json:"error"`
+	Result Wallet `json:"result"`
+}
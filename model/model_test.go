@@ -0,0 +1,108 @@
+package model_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+func TestMarketTradeUnmarshal(t *testing.T) {
+	ret := model.MarketTradesResponse{}
+	data := `{"error":0,"result":[[1529516287,10000.00,0.09975000,"BUY"],{"timestamp":1529516288,"rate":10001,"amount":0.5,"side":"SELL"},null]}`
+	if err := json.Unmarshal([]byte(data), &ret); err != nil {
+		t.Fatal(err)
+	}
+	if len(ret.Result) != 3 {
+		t.Fatalf("expected 3 trades, got %d", len(ret.Result))
+	}
+	trade := ret.Result[0]
+	if trade.Timestamp != 1529516287 || trade.Rate.String() != "10000" || trade.Amount.String() != "0.09975" || trade.Side != "BUY" {
+		t.Fatalf("unexpected trade %+v", trade)
+	}
+	if trade := ret.Result[1]; trade.Timestamp != 1529516288 || trade.Side != "SELL" {
+		t.Fatalf("unexpected trade %+v", trade)
+	}
+}
+
+func TestMarketBooksUnmarshal(t *testing.T) {
+	ret := model.MarketBooksResponse{}
+	data := `{"error":0,"result":{"bids":[[1,1529453033,997.50,10000.00,0.09975000]],"asks":[[680,1529491094,997.50,10000.00,0.09975000]]}}`
+	if err := json.Unmarshal([]byte(data), &ret); err != nil {
+		t.Fatal(err)
+	}
+	ask := ret.Result["asks"][0]
+	if ask.OrderID != 680 || ask.Timestamp != 1529491094 || ask.Volumn.String() != "997.5" || ask.Rate.String() != "10000" || ask.Amount.String() != "0.09975" {
+		t.Fatalf("unexpected ask %+v", ask)
+	}
+	if len(ret.Result["bids"]) != 1 {
+		t.Fatalf("unexpected bids %+v", ret.Result["bids"])
+	}
+}
+
+func TestMarketDepthUnmarshal(t *testing.T) {
+	ret := map[string][]model.MarketDepth{}
+	if err := json.Unmarshal([]byte(`{"asks":[[262600,0.61905798]],"bids":[[262000,0.00108087]]}`), &ret); err != nil {
+		t.Fatal(err)
+	}
+	if bid := ret["bids"][0]; bid.Price.String() != "262000" || bid.Volumn.String() != "0.00108087" {
+		t.Fatalf("unexpected bid %+v", bid)
+	}
+}
+
+func TestWalletUnmarshal(t *testing.T) {
+	ret := model.WalletResponse{}
+	if err := json.Unmarshal([]byte(`{"error":0,"result":{"THB":188379.27,"btc":0.8,"ETH":0}}`), &ret); err != nil {
+		t.Fatal(err)
+	}
+	wallet := ret.Result
+	if wallet.Get("THB").String() != "188379.27" || wallet.Get("BTC").String() != "0.8" || !wallet.Get("xrp").IsZero() {
+		t.Fatalf("unexpected wallet %v", wallet)
+	}
+	if currencies := strings.Join(wallet.Currencies(), ","); currencies != "BTC,ETH,THB" {
+		t.Fatalf("unexpected currencies %s", currencies)
+	}
+	if currencies := strings.Join(wallet.NonZero().Currencies(), ","); currencies != "BTC,THB" {
+		t.Fatalf("unexpected non-zero currencies %s", currencies)
+	}
+}
+
+func TestUnmarshalArrayErrors(t *testing.T) {
+	tests := map[string]string{
+		`{"result":[[1529516287,10000.00,0.09975000]]}`:       "expected 4 elements, got 3",
+		`{"result":[[1529516287,"abc",0.09975000,"BUY"]]}`:    "element 1 (rate)",
+		`{"result":[["1529516287",10000,0.09975000,"BUY"]]}`:  "element 0 (timestamp)",
+		`{"result":[[1529516287,10000.00,0.09975000,false]]}`: "element 3 (side)",
+		`{"result":["BUY"]}`: "expected an array",
+	}
+	for data, want := range tests {
+		ret := model.MarketTradesResponse{}
+		err := json.Unmarshal([]byte(data), &ret)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", data, want, err)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"result":{"bids":[[1,2,3]]}}`), &model.MarketBooksResponse{}); err == nil {
+		t.Error("expected error for a short book entry")
+	}
+	if err := json.Unmarshal([]byte(`{"result":{"THB":{"available":"x"}}}`), &model.BalancesResponse{}); err == nil {
+		t.Error("expected error for an invalid balance")
+	}
+}
+
+func TestBookEventUnmarshal(t *testing.T) {
+	event := model.BookEvent{}
+	data := `{"event":"tradeschanged","pairing_id":1,"data":[[[1529516287,10000,0.1,"BUY",0,0,true,false,false]],[[121.82,112510.1,0.00108283,0,false,false]],[]]}`
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.PairingID != 1 || len(event.Bids) != 1 || event.Bids[0].Rate.String() != "112510.1" || event.Bids[0].Amount.String() != "0.00108283" || event.Asks == nil {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if err := json.Unmarshal([]byte(`{"event":"bidschanged","data":[[1,2]]}`), &event); err == nil {
+		t.Error("expected error for a short book entry")
+	}
+}
@@ -0,0 +1,103 @@
+package model
+
+import "github.com/ChanasinP/bitkub-go/decimal"
+
+type Order struct {
+	ID        int64           `json:"id"`   // order id
+	Hash      string          `json:"hash"` // order hash
+	Type      string          `json:"typ"`  // order type
+	Amount    decimal.Decimal `json:"amt"`  // spending amount
+	Rate      decimal.Decimal `json:"rat"`  // rate
+	Fee       decimal.Decimal `json:"fee"`  // fee
+	Credit    decimal.Decimal `json:"cre"`  // credit used
+	Receive   decimal.Decimal `json:"rec"`  // amount to receive
+	Timestamp int64           `json:"ts"`   // timestamp
+	ClientID  string          `json:"ci"`   // client id sent with the order, empty when none was set
+}
+
+type OrderResponse struct {
+	Error  int   `json:"error"`
+	Result Order `json:"result"`
+}
+
+type OpenOrder struct {
+	ID        int             `json:"id"`        // order id
+	Hash      string          `json:"hash"`      // order hash
+	Side      string          `json:"side"`      // order side: buy or sell
+	Type      string          `json:"type"`      // order type
+	Rate      decimal.Decimal `json:"rate"`      // rate
+	Fee       decimal.Decimal `json:"fee"`       // fee
+	Credit    decimal.Decimal `json:"credit"`    // credit used
+	Amount    decimal.Decimal `json:"amount"`    // amount
+	Receive   decimal.Decimal `json:"receive"`   // amount to receive
+	ParentID  int             `json:"parent_id"` // parent order id
+	SuperID   int             `json:"super_id"`  // super parent order id
+	Timestamp int64           `json:"ts"`        // timestamp
+}
+
+type OpenOrderResponse struct {
+	Error  int         `json:"error"`
+	Result []OpenOrder `json:"result"`
+}
+
+type OrderHistory struct {
+	TxnID         string          `json:"txn_id"`
+	OrderID       int             `json:"order_id"`
+	Hash          string          `json:"hash"`
+	ParentOrderID int             `json:"parent_order_id"`
+	SuperOrderID  int             `json:"super_order_id"`
+	TakenByMe     bool            `json:"taken_by_me"`
+	IsMaker       bool            `json:"is_maker"`
+	Side          string          `json:"side"`
+	Type          string          `json:"type"`
+	Rate          decimal.Decimal `json:"rate"`
+	Fee           decimal.Decimal `json:"fee"`
+	Credit        decimal.Decimal `json:"credit"`
+	Amount        decimal.Decimal `json:"amount"`
+	Receive       decimal.Decimal `json:"receive"`
+	Timestamp     int64           `json:"ts"`
+}
+
+type OrderHistoryPagination struct {
+	Page     int `json:"page"`
+	Last     int `json:"last"`
+	Next     int `json:"next"`
+	Previous int `json:"prev"`
+}
+
+type OrderHistoryResponse struct {
+	Error      int                    `json:"error"`
+	Result     []OrderHistory         `json:"result"`
+	Pagination OrderHistoryPagination `json:"pagination"`
+}
+
+type OrderInfoHistory struct {
+	Amount    decimal.Decimal `json:"amount"`
+	Credit    decimal.Decimal `json:"credit"`
+	Fee       decimal.Decimal `json:"fee"`
+	ID        int64           `json:"id"`
+	Rate      decimal.Decimal `json:"rate"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+type OrderInfo struct {
+	ID            int64              `json:"id"`
+	First         int64              `json:"first"`          // first order id
+	Parent        int64              `json:"parent"`         // parent order id
+	Last          int64              `json:"last"`           // last order id
+	Amount        decimal.Decimal    `json:"amount"`         // order amount
+	Rate          decimal.Decimal    `json:"rate"`           // order rate
+	Fee           decimal.Decimal    `json:"fee"`            // order fee
+	Credit        decimal.Decimal    `json:"credit"`         // order fee credit used
+	Filled        decimal.Decimal    `json:"filled"`         // filled amount
+	Total         decimal.Decimal    `json:"total"`          // total amount
+	Status        string             `json:"status"`         // order status: filled, unfilled
+	PartialFilled bool               `json:"partial_filled"` // true when order has been partially filled, false when not filled or fully filled
+	Remaining     decimal.Decimal    `json:"remaining"`      // remaining amount to be executed
+	History       []OrderInfoHistory `json:"history"`        // order history
+}
+
+type OrderInfoResponse struct {
+	Error  int       `json:"error"`
+	Result OrderInfo `json:"result"`
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+// TradeEvent is a message of the market.trade.<symbol> stream.
+type TradeEvent struct {
+	Stream      string          `json:"stream"` // stream name, e.g. market.trade.thb_btc
+	Symbol      string          `json:"sym"`    // symbol, e.g. THB_BTC
+	TxnID       string          `json:"txn"`    // transaction id
+	Rate        decimal.Decimal `json:"rat"`    // rate
+	Amount      decimal.Decimal `json:"amt"`    // amount
+	BuyOrderID  int64           `json:"bid"`    // buy order id
+	SellOrderID int64           `json:"sid"`    // sell order id
+	Timestamp   int64           `json:"ts"`     // timestamp
+	Backfilled  bool            `json:"-"`      // fetched with GetMarketTrades after a reconnect, without txn and order ids
+}
+
+// TickerEvent is a message of the market.ticker.<symbol> stream.
+type TickerEvent struct {
+	Stream        string          `json:"stream"` // stream name, e.g. market.ticker.thb_btc
+	ID            int64           `json:"id"`     // symbol id
+	Last          decimal.Decimal `json:"last"`
+	LowestAsk     decimal.Decimal `json:"lowestAsk"`
+	HighestBid    decimal.Decimal `json:"highestBid"`
+	Change        decimal.Decimal `json:"change"`
+	PercentChange decimal.Decimal `json:"percentChange"`
+	BaseVolume    decimal.Decimal `json:"baseVolume"`
+	QuoteVolume   decimal.Decimal `json:"quoteVolume"`
+	IsFrozen      int64           `json:"isFrozen"`
+	High24hr      decimal.Decimal `json:"high24hr"`
+	Low24hr       decimal.Decimal `json:"low24hr"`
+	Open          decimal.Decimal `json:"open"`
+	Close         decimal.Decimal `json:"close"`
+}
+
+// StreamAuthEvent answers the authentication of a private stream.
+type StreamAuthEvent struct {
+	Event   string `json:"event"`   // auth
+	Code    string `json:"code"`    // 200 when authenticated
+	Message string `json:"message"` // reason of a failure
+}
+
+// OrderUpdateEvent is a message of the order_update channel of the private stream, sent when an
+// order is created, filled, partially filled or cancelled.
+type OrderUpdateEvent struct {
+	OrderID         string          `json:"order_id"`         // order id
+	ClientID        string          `json:"client_id"`        // client order id, empty when none was set
+	Symbol          string          `json:"symbol"`           // symbol, e.g. BTC_THB
+	Side            string          `json:"side"`             // buy or sell
+	Type            string          `json:"type"`             // limit or market
+	Status          string          `json:"status"`           // new, partial_filled, filled or cancelled
+	Rate            decimal.Decimal `json:"rate"`             // rate
+	Amount          decimal.Decimal `json:"amount"`           // amount of the order
+	FilledAmount    decimal.Decimal `json:"filled_amount"`    // amount of the base asset filled so far
+	RemainingAmount decimal.Decimal `json:"remaining_amount"` // amount still open
+	AvgFilledPrice  decimal.Decimal `json:"avg_filled_price"` // average rate of the fills
+	Fee             decimal.Decimal `json:"fee"`              // fee paid so far
+	Timestamp       int64           `json:"ts"`               // timestamp in milliseconds
+}
+
+// FillEvent is a message of the match_update channel of the private stream, sent for each fill of an order.
+type FillEvent struct {
+	TxnID     string          `json:"txn_id"`    // transaction id
+	OrderID   string          `json:"order_id"`  // order id
+	ClientID  string          `json:"client_id"` // client order id, empty when none was set
+	Symbol    string          `json:"symbol"`    // symbol, e.g. BTC_THB
+	Side      string          `json:"side"`      // buy or sell
+	Rate      decimal.Decimal `json:"rate"`      // rate
+	Amount    decimal.Decimal `json:"amount"`    // filled amount
+	Fee       decimal.Decimal `json:"fee"`       // fee
+	IsMaker   bool            `json:"is_maker"`  // true when the order was resting in the book
+	Timestamp int64           `json:"ts"`        // timestamp in milliseconds
+}
+
+// Events of the orderbook stream.
+const (
+	BookBidsChanged   = "bidschanged"   // Bids holds the top of the bids
+	BookAsksChanged   = "askschanged"   // Asks holds the top of the asks
+	BookTradesChanged = "tradeschanged" // a trade was made, Bids and Asks hold the top of both sides
+)
+
+// BookEntry is an order of the orderbook stream, given as [volume, rate, amount, ...].
+type BookEntry struct {
+	Volume decimal.Decimal // value of the order in the quote currency
+	Rate   decimal.Decimal // rate
+	Amount decimal.Decimal // amount
+}
+
+// UnmarshalJSON decodes an order given as [volume, rate, amount, reserved, is new, is user owner].
+func (e *BookEntry) UnmarshalJSON(data []byte) error {
+	return unmarshalArray(data, "book entry",
+		arrayField{"volume", &e.Volume},
+		arrayField{"rate", &e.Rate},
+		arrayField{"amount", &e.Amount},
+	)
+}
+
+// BookEvent is a message of the orderbook/<symbol id> stream.
+type BookEvent struct {
+	Event     string      // BookBidsChanged, BookAsksChanged or BookTradesChanged
+	PairingID int         // symbol id
+	Bids      []BookEntry // best first
+	Asks      []BookEntry // best first
+}
+
+// UnmarshalJSON decodes the data of the event: the bids or asks, or [trades, bids, asks] for BookTradesChanged.
+func (e *BookEvent) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Event     string          `json:"event"`
+		PairingID int             `json:"pairing_id"`
+		Data      json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	e.Event, e.PairingID = raw.Event, raw.PairingID
+
+	switch raw.Event {
+	case BookBidsChanged:
+		return json.Unmarshal(raw.Data, &e.Bids)
+	case BookAsksChanged:
+		return json.Unmarshal(raw.Data, &e.Asks)
+	case BookTradesChanged:
+		var trades json.RawMessage
+		return unmarshalArray(raw.Data, "book trades",
+			arrayField{"trades", &trades},
+			arrayField{"bids", &e.Bids},
+			arrayField{"asks", &e.Asks},
+		)
+	}
+	return fmt.Errorf("decode book event: unknown event %q", raw.Event)
+}
@@ -0,0 +1,30 @@
+package model
+
+import "github.com/ChanasinP/bitkub-go/decimal"
+
+/*
+{
+    "id": 1,
+    "last": 216415.00,
+    "lowestAsk": 216678.00,
+    "highestBid": 215000.00,
+    "percentChange": 1.91,
+    "baseVolume": 71.02603946,
+    "quoteVolume": 15302897.99,
+    "isFrozen": 0,
+    "high24hr": 221396.00,
+    "low24hr": 206414.00
+}
+*/
+type MarketTicker struct {
+	ID            int64           `json:"id"`
+	Last          decimal.Decimal `json:"last"`
+	LowestAsk     decimal.Decimal `json:"lowestAsk"`
+	HighestBid    decimal.Decimal `json:"highestBid"`
+	PercentChange decimal.Decimal `json:"percentChange"`
+	BaseVolume    decimal.Decimal `json:"baseVolume"`
+	QuoteVolume   decimal.Decimal `json:"quoteVolume"`
+	IsFrozen      int64           `json:"isFrozen"`
+	High24hr      decimal.Decimal `json:"high24hr"`
+	Low24hr       decimal.Decimal `json:"low24hr"`
+}
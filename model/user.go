@@ -0,0 +1,44 @@
+package model
+
+import "github.com/ChanasinP/bitkub-go/decimal"
+
+type Limit struct {
+	Deposit  decimal.Decimal `json:"deposit"`  // value equivalent
+	Withdraw decimal.Decimal `json:"withdraw"` // value equivalent
+}
+
+// Limits limitations by kyc level
+type Limits struct {
+	Crypto Limit `json:"crypto"` // BTC limit
+	Fiat   Limit `json:"fiat"`   // THB limit
+}
+
+type CryptoUsage struct {
+	Deposit               decimal.Decimal `json:"deposit"`  // BTC value equivalent
+	Withdraw              decimal.Decimal `json:"withdraw"` // BTC value equivalent
+	DepositPercentage     decimal.Decimal `json:"deposit_percentage"`
+	WithdrawPercentage    decimal.Decimal `json:"withdraw_percentage"`
+	DepositTHBEquivalent  decimal.Decimal `json:"deposit_thb_equivalent"`  // THB value equivalent
+	WithdrawTHBEquivalent decimal.Decimal `json:"withdraw_thb_equivalent"` // THB value equivalent
+}
+
+type FiatUsage struct {
+	Deposit            decimal.Decimal `json:"deposit"`  // THB value equivalent
+	Withdraw           decimal.Decimal `json:"withdraw"` // THB value equivalent
+	DepositPercentage  decimal.Decimal `json:"deposit_percentage"`
+	WithdrawPercentage decimal.Decimal `json:"withdraw_percentage"`
+}
+
+type UserLimits struct {
+	Limits Limits `json:"limits"`
+	Usage  struct {
+		Crypto CryptoUsage `json:"crypto"`
+		Fiat   FiatUsage   `json:"fiat"`
+	} `json:"usage"`
+	Rate decimal.Decimal `json:"rate"` // current THB rate used to calculate
+}
+
+type UserLimitsResponse struct {
+	Error  int        `json:"error"`
+	Result UserLimits `json:"result"`
+}
@@ -0,0 +1,103 @@
+package model
+
+import "github.com/ChanasinP/bitkub-go/decimal"
+
+// OrderV3 is the order returned by the v3 place-bid and place-ask endpoints.
+type OrderV3 struct {
+	ID        string          `json:"id"`   // order id
+	Hash      string          `json:"hash"` // order hash
+	Type      string          `json:"typ"`  // order type
+	Amount    decimal.Decimal `json:"amt"`  // spending amount
+	Rate      decimal.Decimal `json:"rat"`  // rate
+	Fee       decimal.Decimal `json:"fee"`  // fee
+	Credit    decimal.Decimal `json:"cre"`  // credit used
+	Receive   decimal.Decimal `json:"rec"`  // amount to receive
+	Timestamp int64           `json:"ts"`   // timestamp
+	ClientID  string          `json:"ci"`   // client id
+}
+
+type OrderV3Response struct {
+	Error  int     `json:"error"`
+	Result OrderV3 `json:"result"`
+}
+
+type OpenOrderV3 struct {
+	ID        string          `json:"id"`        // order id
+	Hash      string          `json:"hash"`      // order hash
+	Side      string          `json:"side"`      // order side: buy or sell
+	Type      string          `json:"type"`      // order type
+	Rate      decimal.Decimal `json:"rate"`      // rate
+	Fee       decimal.Decimal `json:"fee"`       // fee
+	Credit    decimal.Decimal `json:"credit"`    // credit used
+	Amount    decimal.Decimal `json:"amount"`    // amount
+	Receive   decimal.Decimal `json:"receive"`   // amount to receive
+	ParentID  string          `json:"parent_id"` // parent order id
+	SuperID   string          `json:"super_id"`  // super parent order id
+	ClientID  string          `json:"client_id"` // client id
+	Timestamp int64           `json:"ts"`        // timestamp
+}
+
+type OpenOrderV3Response struct {
+	Error  int           `json:"error"`
+	Result []OpenOrderV3 `json:"result"`
+}
+
+type OrderHistoryV3 struct {
+	TxnID         string          `json:"txn_id"`
+	OrderID       string          `json:"order_id"`
+	Hash          string          `json:"hash"`
+	ParentOrderID string          `json:"parent_order_id"`
+	SuperOrderID  string          `json:"super_order_id"`
+	ClientID      string          `json:"client_id"`
+	TakenByMe     bool            `json:"taken_by_me"`
+	IsMaker       bool            `json:"is_maker"`
+	Side          string          `json:"side"`
+	Type          string          `json:"type"`
+	Rate          decimal.Decimal `json:"rate"`
+	Fee           decimal.Decimal `json:"fee"`
+	Credit        decimal.Decimal `json:"credit"`
+	Amount        decimal.Decimal `json:"amount"`
+	Timestamp     int64           `json:"ts"`
+}
+
+type OrderHistoryV3Response struct {
+	Error      int                    `json:"error"`
+	Result     []OrderHistoryV3       `json:"result"`
+	Pagination OrderHistoryPagination `json:"pagination"`
+}
+
+type OrderInfoHistoryV3 struct {
+	TxnID     string          `json:"txn_id"`
+	ID        string          `json:"id"`
+	Amount    decimal.Decimal `json:"amount"`
+	Credit    decimal.Decimal `json:"credit"`
+	Fee       decimal.Decimal `json:"fee"`
+	Rate      decimal.Decimal `json:"rate"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+type OrderInfoV3 struct {
+	ID            string               `json:"id"`
+	First         string               `json:"first"`          // first order id
+	Parent        string               `json:"parent"`         // parent order id
+	Last          string               `json:"last"`           // last order id
+	ClientID      string               `json:"client_id"`      // client id
+	PostOnly      bool                 `json:"post_only"`      // post only order
+	Side          string               `json:"side"`           // order side: buy or sell
+	Type          string               `json:"type"`           // order type
+	Amount        decimal.Decimal      `json:"amount"`         // order amount
+	Rate          decimal.Decimal      `json:"rate"`           // order rate
+	Fee           decimal.Decimal      `json:"fee"`            // order fee
+	Credit        decimal.Decimal      `json:"credit"`         // order fee credit used
+	Filled        decimal.Decimal      `json:"filled"`         // filled amount
+	Total         decimal.Decimal      `json:"total"`          // total amount
+	Status        string               `json:"status"`         // order status: filled, unfilled, cancelled
+	PartialFilled bool                 `json:"partial_filled"` // true when order has been partially filled, false when not filled or fully filled
+	Remaining     decimal.Decimal      `json:"remaining"`      // remaining amount to be executed
+	History       []OrderInfoHistoryV3 `json:"history"`        // order history
+}
+
+type OrderInfoV3Response struct {
+	Error  int         `json:"error"`
+	Result OrderInfoV3 `json:"result"`
+}
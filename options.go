@@ -0,0 +1,122 @@
+package bitkub
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/internal"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// FastHTTPDoer is implemented by *fasthttp.Client and *fasthttp.HostClient.
+type FastHTTPDoer = internal.FastHTTPDoer
+
+// WithBaseURL sets the API base URL, e.g. to point the client at a local stand-in or a proxy.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithWebSocketURL sets the base URL of the WebSocket streams, e.g. to point streams at a local server.
+func WithWebSocketURL(wsURL string) Option {
+	return func(c *Client) {
+		c.wsURL = strings.TrimRight(wsURL, "/")
+	}
+}
+
+// WithTimeout sets the timeout of each request. It defaults to 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.Timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader adds a header sent with each request. Headers set by the client itself take precedence.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers[key] = value
+	}
+}
+
+// WithFastHTTPClient sends requests with the given fasthttp client, e.g. a *fasthttp.HostClient configured with a proxy dialer.
+func WithFastHTTPClient(client FastHTTPDoer) Option {
+	return func(c *Client) {
+		c.transport = &internal.FastHTTPTransport{Client: client}
+	}
+}
+
+// WithRoundTripper sends requests with net/http using the given RoundTripper instead of fasthttp.
+func WithRoundTripper(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = &internal.HTTPTransport{RoundTripper: rt}
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests. It defaults to DefaultRetryPolicy, nil disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimiter sets the limiter throttling requests. By default clients using the same API key share
+// a blocking limiter with DefaultRateLimits, nil disables client-side rate limiting.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithClockSync measures the offset to the Bitkub server clock and applies it to the timestamp of
+// signed payloads, avoiding "Invalid timestamp" errors on hosts with a drifting clock. The offset is
+// measured again by the first signed request made once it is older than interval, see SyncClock.
+func WithClockSync(interval time.Duration) Option {
+	return func(c *Client) {
+		c.clock.interval = interval
+	}
+}
+
+// WithSymbolRegistry validates the symbol, precision and minimum size of orders against registry
+// before sending them, and the symbol of the order queries and cancels. The registry is loaded with
+// the client when first needed.
+func WithSymbolRegistry(registry *SymbolRegistry) Option {
+	return func(c *Client) {
+		c.symbols = registry
+	}
+}
+
+// WithClientIDGenerator generates the client order id of the orders placed without one, e.g.
+// NewClientID.
+func WithClientIDGenerator(generate func() string) Option {
+	return func(c *Client) {
+		c.clientID = generate
+	}
+}
+
+// WithOrderLookupPolicy sets the policy deciding how long PlaceOrRecover keeps looking up an order
+// whose outcome is unknown. It defaults to DefaultOrderLookupPolicy.
+func WithOrderLookupPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.lookup = policy
+	}
+}
+
+// WithDryRun makes the client rehearse the requests changing the account, see DryRunRequest.
+// Orders are sent to the test endpoints, and cancels, withdrawals and the other changes are not
+// sent at all. Each of them is passed to log, which may be nil.
+func WithDryRun(log func(DryRunRequest)) Option {
+	return func(c *Client) {
+		c.dryRun, c.dryRunLog = true, log
+	}
+}
@@ -0,0 +1,48 @@
+package bitkub_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/status" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if ua := r.Header.Get("User-Agent"); ua != "my-bot/2.0" {
+			t.Errorf("unexpected user agent %q", ua)
+		}
+		if v := r.Header.Get("X-Custom"); v != "1" {
+			t.Errorf("unexpected custom header %q", v)
+		}
+		w.Write([]byte(`[{"name":"Non-secure endpoints","status":"ok","message":""}]`))
+	}))
+	defer srv.Close()
+
+	for name, transport := range map[string]bitkub.Option{
+		"fasthttp":   bitkub.WithFastHTTPClient(&fasthttp.Client{}),
+		"hostclient": bitkub.WithFastHTTPClient(&fasthttp.HostClient{Addr: srv.Listener.Addr().String()}),
+		"net/http":   bitkub.WithRoundTripper(http.DefaultTransport),
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := bitkub.NewClient("", "",
+				bitkub.WithBaseURL(srv.URL+"/"),
+				bitkub.WithUserAgent("my-bot/2.0"),
+				bitkub.WithHeader("X-Custom", "1"),
+				transport,
+			)
+			status, err := api.GetServerStatus()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(status) != 1 || status[0].Status != "ok" {
+				t.Fatalf("unexpected status %+v", status)
+			}
+		})
+	}
+}
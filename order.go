@@ -0,0 +1,279 @@
+package bitkub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// OrderRequest is an order to place with PlaceOrder, built with BuyOrder or SellOrder, e.g.
+//
+//	req := bitkub.BuyOrder("THB_BTC").Limit(rate).BaseAmount(amount).ClientID("my-order-1")
+//
+// With a symbol registry, see WithSymbolRegistry, the order is checked against the rules of its
+// symbol before it is sent.
+type OrderRequest struct {
+	symbol       string
+	side         string
+	bitType      string
+	amount       decimal.Decimal
+	rate         decimal.Decimal
+	byQuote      bool // amount is of the quote asset
+	byBase       bool // amount is of the base asset
+	clientID     string
+	test         bool
+	round        bool
+	checkBalance bool
+}
+
+// BuyOrder starts a buy order of symbol in any form, e.g. THB_BTC or btc_thb.
+func BuyOrder(symbol string) *OrderRequest {
+	return &OrderRequest{symbol: symbol, side: OrderSideBuy}
+}
+
+// SellOrder starts a sell order of symbol in any form, e.g. THB_BTC or btc_thb.
+func SellOrder(symbol string) *OrderRequest {
+	return &OrderRequest{symbol: symbol, side: OrderSideSell}
+}
+
+// newOrderRequest is an order of the positional arguments of the older order methods.
+func newOrderRequest(side, symbol, bitType string, amount, rate decimal.Decimal, clientID []string) *OrderRequest {
+	r := &OrderRequest{symbol: symbol, side: side, bitType: bitType, amount: amount, rate: rate}
+	if len(clientID) > 0 {
+		r.clientID = clientID[0]
+	}
+	return r
+}
+
+// Limit makes a limit order at rate.
+func (r *OrderRequest) Limit(rate decimal.Decimal) *OrderRequest {
+	r.bitType, r.rate = OrderTypeLimit, rate
+	return r
+}
+
+// Market makes a market order.
+func (r *OrderRequest) Market() *OrderRequest {
+	r.bitType, r.rate = OrderTypeMarket, decimal.Zero
+	return r
+}
+
+// Amount sets the amount the way Bitkub expects it: the quote asset to spend for a buy order and
+// the base asset to sell for a sell order.
+func (r *OrderRequest) Amount(amount decimal.Decimal) *OrderRequest {
+	r.amount, r.byQuote, r.byBase = amount, false, false
+	return r
+}
+
+// BaseAmount sets the amount of the base asset, e.g. BTC. A buy order by base amount must be a
+// limit order, its amount is converted to the quote asset at the rate and rounded down.
+func (r *OrderRequest) BaseAmount(amount decimal.Decimal) *OrderRequest {
+	r.amount, r.byQuote, r.byBase = amount, false, true
+	return r
+}
+
+// QuoteAmount sets the amount of the quote asset, e.g. THB. A sell order by quote amount is placed
+// with PlaceAskByFiat.
+func (r *OrderRequest) QuoteAmount(amount decimal.Decimal) *OrderRequest {
+	r.amount, r.byQuote, r.byBase = amount, true, false
+	return r
+}
+
+// ClientID sets the client order id, which lets the order be found with FindOrder when its outcome
+// is unknown, see PlaceOrRecover. Without one, the id is generated when the client has a generator, see WithClientIDGenerator.
+func (r *OrderRequest) ClientID(id string) *OrderRequest {
+	r.clientID = id
+	return r
+}
+
+// Test sends the order to the test endpoint, which validates it without placing it.
+func (r *OrderRequest) Test() *OrderRequest {
+	r.test = true
+	return r
+}
+
+// Rounded rounds the rate and amount to the precision of the symbol instead of rejecting them:
+// the rate of a buy order down and of a sell order up, and the amount down.
+func (r *OrderRequest) Rounded() *OrderRequest {
+	r.round = true
+	return r
+}
+
+// CheckBalance checks the available balance of GetBalances before the order is sent, failing with
+// an error matching both ErrInvalidOrder and ErrInsufficientBalance. The balance of a market sell
+// order by quote amount is not checked, its amount of the base asset is unknown.
+func (r *OrderRequest) CheckBalance() *OrderRequest {
+	r.checkBalance = true
+	return r
+}
+
+// Side returns the side of the order, OrderSideBuy or OrderSideSell.
+func (r *OrderRequest) Side() string { return r.side }
+
+// Symbol returns the symbol of the order as given.
+func (r *OrderRequest) Symbol() string { return r.symbol }
+
+// placedOrder is an order request resolved against the rules of its symbol.
+type placedOrder struct {
+	symbol   string
+	bitType  string
+	amount   decimal.Decimal
+	rate     decimal.Decimal
+	byQuote  bool
+	clientID string
+}
+
+// resolve validates the request and converts it to the amount Bitkub expects, in the legacy or v3
+// form of the symbol. Balances are only fetched when the request checks the balance.
+func (r *OrderRequest) resolve(ctx context.Context, c *Client, v3 bool, balances func(context.Context) (map[string]model.Balance, error)) (placedOrder, error) {
+	if r.symbol == "" {
+		return placedOrder{}, fmt.Errorf("symbol is empty")
+	}
+	if r.side != OrderSideBuy && r.side != OrderSideSell {
+		return placedOrder{}, fmt.Errorf("side is invalid")
+	}
+	if r.bitType == "" {
+		return placedOrder{}, fmt.Errorf("bit type is empty")
+	}
+	if r.bitType != OrderTypeLimit && r.bitType != OrderTypeMarket {
+		return placedOrder{}, fmt.Errorf("bit type is invalid")
+	}
+	if !r.amount.IsPositive() {
+		return placedOrder{}, fmt.Errorf("%w: amount %s is not positive", ErrInvalidOrder, r.amount)
+	}
+
+	order := placedOrder{symbol: r.symbol, bitType: r.bitType, amount: r.amount, rate: r.rate, byQuote: r.byQuote, clientID: c.clientIDOf(r, false)}
+	if order.bitType == OrderTypeMarket {
+		order.rate = decimal.Zero
+	} else if !order.rate.IsPositive() {
+		return placedOrder{}, fmt.Errorf("%w: rate %s of a limit order is not positive", ErrInvalidOrder, order.rate)
+	}
+
+	rules, symbol, err := c.checkSymbol(ctx, r.symbol, v3)
+	if err != nil {
+		return placedOrder{}, err
+	}
+	order.symbol = symbol
+
+	if r.round && rules.HasRules && order.bitType == OrderTypeLimit {
+		if r.side == OrderSideBuy {
+			order.rate = order.rate.RoundFloor(rules.PricePrecision)
+		} else {
+			order.rate = order.rate.RoundCeil(rules.PricePrecision)
+		}
+	}
+	if r.side == OrderSideBuy {
+		if r.byBase {
+			if order.bitType == OrderTypeMarket {
+				return placedOrder{}, fmt.Errorf("%w: a market buy order cannot be by base amount", ErrInvalidOrder)
+			}
+			order.amount = order.amount.Mul(order.rate)
+			if rules.HasRules {
+				order.amount = order.amount.RoundFloor(rules.QuotePrecision)
+			}
+		}
+		// the amount of a buy order is always of the quote asset
+		order.byQuote = false
+	}
+	if r.round && rules.HasRules {
+		precision := rules.AmountPrecision
+		if r.side == OrderSideBuy || order.byQuote {
+			precision = rules.QuotePrecision
+		}
+		order.amount = order.amount.RoundFloor(precision)
+	}
+	if err := rules.ValidateOrder(r.side, order.amount, order.rate, order.byQuote); err != nil {
+		return placedOrder{}, err
+	}
+
+	if r.checkBalance {
+		if err := order.checkBalance(ctx, r.side, rules.Symbol, r.symbol, balances); err != nil {
+			return placedOrder{}, err
+		}
+	}
+	return order, nil
+}
+
+func (o placedOrder) checkBalance(ctx context.Context, side string, symbol Symbol, given string, balances func(context.Context) (map[string]model.Balance, error)) error {
+	if symbol.Base == "" {
+		var err error
+		if symbol, err = ParseSymbol(given); err != nil {
+			return err
+		}
+	}
+
+	currency, needed := symbol.Quote, o.amount
+	if side == OrderSideSell {
+		currency = symbol.Base
+		if o.byQuote {
+			if o.rate.IsZero() {
+				return nil
+			}
+			needed = o.amount.DivRound(o.rate, 8)
+		}
+	}
+
+	available, err := balances(ctx)
+	if err != nil {
+		return fmt.Errorf("check balance: %w", err)
+	}
+	if balance := available[currency]; balance.Available.LessThan(needed) {
+		return &orderError{code: ErrInsufficientBalance, msg: fmt.Sprintf("%s %s needed, %s available", needed, currency, balance.Available)}
+	}
+	return nil
+}
+
+// payload returns the payload of the order. Amounts and rates are sent as exact decimal strings, like
+// in every other payload.
+func (o placedOrder) payload() map[string]interface{} {
+	payload := map[string]interface{}{
+		"sym": o.symbol,
+		"typ": o.bitType,
+		"amt": o.amount.String(),
+		"rat": o.rate.String(),
+	}
+	if o.clientID != "" {
+		payload["client_id"] = o.clientID
+	}
+	return payload
+}
+
+// PlaceOrder Create a buy or sell order, see OrderRequest.
+func (b *Client) PlaceOrder(req *OrderRequest) (*model.Order, error) {
+	return b.PlaceOrderCtx(context.Background(), req)
+}
+
+// PlaceOrderCtx is like PlaceOrder but carries ctx for cancellation and deadlines.
+func (b *Client) PlaceOrderCtx(ctx context.Context, req *OrderRequest) (*model.Order, error) {
+	order, err := req.resolve(ctx, b, false, b.GetBalancesCtx)
+	if err != nil {
+		return nil, err
+	}
+	return b.sendOrder(ctx, req, order)
+}
+
+func (b *Client) sendOrder(ctx context.Context, req *OrderRequest, order placedOrder) (*model.Order, error) {
+	path := "/api/market/place-bid"
+	if req.side == OrderSideSell {
+		path = "/api/market/place-ask"
+		if order.byQuote {
+			if req.test {
+				return nil, fmt.Errorf("a sell order by quote amount has no test endpoint")
+			}
+			path = "/api/market/place-ask-by-fiat"
+		}
+	}
+	if req.test {
+		path += "/test"
+	}
+
+	ret := model.OrderResponse{}
+	if err := b.post(ctx, path, order.payload(), &ret); err != nil {
+		return nil, err
+	}
+	if ret.Result.ClientID == "" {
+		ret.Result.ClientID = order.clientID
+	}
+	return &ret.Result, nil
+}
@@ -0,0 +1,99 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+type sentOrder struct {
+	path    string
+	payload map[string]interface{}
+}
+
+func orderServer(t *testing.T) (*bitkub.Client, *[]sentOrder) {
+	sent := []sentOrder{}
+	mux := symbolMux(nil)
+	mux.HandleFunc("/api/market/balances", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":0,"result":{"THB":{"available":500,"reserved":0},"BTC":{"available":0.001,"reserved":0}}}`))
+	})
+	mux.HandleFunc("/api/market/", func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, sentOrder{path: r.URL.Path, payload: payloadOf(r)})
+		w.Write([]byte(`{"error":0,"result":{"id":1}}`))
+	})
+	return testClient(t, mux, bitkub.WithSymbolRegistry(bitkub.NewSymbolRegistry())), &sent
+}
+
+func TestPlaceOrder(t *testing.T) {
+	api, sent := orderServer(t)
+	d := decimal.RequireFromString
+
+	tests := []struct {
+		name string
+		req  *bitkub.OrderRequest
+		path string
+		amt  string
+		rat  string
+	}{
+		{"buy by quote", bitkub.BuyOrder("btc_thb").Limit(d("1000000")).Amount(d("100")), "/api/market/place-bid", "100", "1000000"},
+		{"buy by base", bitkub.BuyOrder("THB_BTC").Limit(d("1000000")).BaseAmount(d("0.0001234")), "/api/market/place-bid", "123.4", "1000000"},
+		{"sell by quote", bitkub.SellOrder("THB_BTC").Market().QuoteAmount(d("100")), "/api/market/place-ask-by-fiat", "100", "0"},
+		{"rounded", bitkub.SellOrder("THB_BTC").Limit(d("1000000.001")).Amount(d("0.000123456")).Rounded(), "/api/market/place-ask", "0.00012345", "1000000.01"},
+		{"test", bitkub.BuyOrder("THB_BTC").Market().Amount(d("100")).Test(), "/api/market/place-bid/test", "100", "0"},
+	}
+	for _, test := range tests {
+		*sent = nil
+		if _, err := api.PlaceOrder(test.req); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(*sent) != 1 {
+			t.Fatalf("%s: expected one order, got %d", test.name, len(*sent))
+		}
+		order := (*sent)[0]
+		if order.path != test.path || order.payload["sym"] != "THB_BTC" || order.payload["amt"] != test.amt || order.payload["rat"] != test.rat {
+			t.Errorf("%s: unexpected order %s %v", test.name, order.path, order.payload)
+		}
+	}
+}
+
+func TestPlaceOrderValidation(t *testing.T) {
+	api, sent := orderServer(t)
+	d := decimal.RequireFromString
+
+	invalid := map[string]struct {
+		req *bitkub.OrderRequest
+		err error
+	}{
+		"no amount":         {bitkub.BuyOrder("THB_BTC").Market(), bitkub.ErrInvalidOrder},
+		"no rate":           {bitkub.BuyOrder("THB_BTC").Limit(decimal.Zero).Amount(d("100")), bitkub.ErrInvalidOrder},
+		"market by base":    {bitkub.BuyOrder("THB_BTC").Market().BaseAmount(d("0.001")), bitkub.ErrInvalidOrder},
+		"too low":           {bitkub.SellOrder("THB_BTC").Limit(d("1000000")).Amount(d("0.000005")), bitkub.ErrAmountTooLow},
+		"not rounded":       {bitkub.SellOrder("THB_BTC").Limit(d("1000000.001")).Amount(d("0.0001")), bitkub.ErrInvalidOrder},
+		"buy balance":       {bitkub.BuyOrder("THB_BTC").Market().Amount(d("501")).CheckBalance(), bitkub.ErrInsufficientBalance},
+		"sell balance":      {bitkub.SellOrder("THB_BTC").Market().Amount(d("0.002")).CheckBalance(), bitkub.ErrInsufficientBalance},
+		"sell by quote":     {bitkub.SellOrder("THB_BTC").Limit(d("1000000")).QuoteAmount(d("1001")).CheckBalance(), bitkub.ErrInsufficientBalance},
+		"unknown symbol":    {bitkub.BuyOrder("THB_XYZ").Market().Amount(d("100")), bitkub.ErrUnknownSymbol},
+		"test sell by fiat": {bitkub.SellOrder("THB_BTC").Market().QuoteAmount(d("100")).Test(), nil},
+	}
+	for name, test := range invalid {
+		*sent = nil
+		_, err := api.PlaceOrder(test.req)
+		if err == nil || (test.err != nil && !errors.Is(err, test.err)) {
+			t.Errorf("%s: expected %v, got %v", name, test.err, err)
+		}
+		if len(*sent) != 0 {
+			t.Errorf("%s: the order was sent", name)
+		}
+	}
+
+	if _, err := api.PlaceOrder(bitkub.BuyOrder("THB_BTC").Market().Amount(d("500")).CheckBalance()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.V3().PlaceOrder(context.Background(), bitkub.SellOrder("THB_BTC").Market().QuoteAmount(d("100"))); err == nil {
+		t.Fatal("expected v3 to reject a sell order by quote amount")
+	}
+}
@@ -0,0 +1,350 @@
+package bitkub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+const bookStreamPath = "/orderbook/"
+
+// ErrBookDesync is the cause of the reconnect of an OrderBook whose bids and asks crossed.
+var ErrBookDesync = errors.New("order book out of sync")
+
+// PriceLevel is the total of the orders at a rate.
+type PriceLevel struct {
+	Rate   decimal.Decimal
+	Amount decimal.Decimal // amount of the orders
+	Volume decimal.Decimal // value of the orders in the quote currency
+}
+
+// OrderBook is a live order book of a symbol. It is seeded with GetMarketBooks on each connection
+// and kept up to date with the orderbook stream. Its queries are safe for concurrent use.
+//
+// The stream sends the top of a side on each change, which replaces the levels down to the worst
+// rate sent while deeper levels are kept from the snapshot. A side sent without levels is left
+// unchanged, as it cannot tell how deep the change went. When the bids and asks cross, the
+// book reconnects with ErrBookDesync to take a new snapshot. Synced reports false meanwhile.
+type OrderBook struct {
+	client *Client
+	symbol string
+	runner *streamRunner
+
+	mu       sync.RWMutex
+	id       int
+	bids     []PriceLevel // highest rate first
+	asks     []PriceLevel // lowest rate first
+	synced   bool
+	onUpdate func(*OrderBook)
+	onError  func(error)
+	onState  func(StreamStateEvent)
+}
+
+// NewOrderBook creates the order book of symbol, e.g. THB_BTC. Call Run to fill it.
+func (b *Client) NewOrderBook(symbol string, opts ...StreamOption) *OrderBook {
+	book := &OrderBook{client: b, symbol: strings.ToUpper(symbol)}
+	book.runner = &streamRunner{
+		config:    newStreamConfig(opts),
+		dial:      book.dial,
+		connected: book.snapshot,
+		handle:    book.dispatch,
+		onState:   book.state,
+	}
+	return book
+}
+
+// Symbol returns the symbol of the book.
+func (o *OrderBook) Symbol() string {
+	return o.symbol
+}
+
+// OnUpdate sets the handler called after the snapshot and each change of the book.
+// It is called from the goroutine running Run and may query the book.
+func (o *OrderBook) OnUpdate(handler func(*OrderBook)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.onUpdate = handler
+}
+
+// OnError sets the handler of messages which cannot be decoded. Such messages are skipped.
+func (o *OrderBook) OnError(handler func(error)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.onError = handler
+}
+
+// OnStateChange sets the handler of connection state changes. StreamConnected is reported once the snapshot is taken.
+func (o *OrderBook) OnStateChange(handler func(StreamStateEvent)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.onState = handler
+}
+
+// Run connects and keeps the book up to date until ctx is done or the reconnect policy gives up.
+func (o *OrderBook) Run(ctx context.Context) error {
+	return o.runner.run(ctx)
+}
+
+// Synced reports whether the book is connected and consistent. The queries answer with the last
+// known state otherwise.
+func (o *OrderBook) Synced() bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.synced
+}
+
+// BestBid returns the highest bid, false when there is none.
+func (o *OrderBook) BestBid() (PriceLevel, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if len(o.bids) == 0 {
+		return PriceLevel{}, false
+	}
+	return o.bids[0], true
+}
+
+// BestAsk returns the lowest ask, false when there is none.
+func (o *OrderBook) BestAsk() (PriceLevel, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if len(o.asks) == 0 {
+		return PriceLevel{}, false
+	}
+	return o.asks[0], true
+}
+
+// Spread returns the best ask minus the best bid, false when a side is empty.
+func (o *OrderBook) Spread() (decimal.Decimal, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if len(o.bids) == 0 || len(o.asks) == 0 {
+		return decimal.Zero, false
+	}
+	return o.asks[0].Rate.Sub(o.bids[0].Rate), true
+}
+
+// Bids returns up to n bids, highest first. n <= 0 returns all of them.
+func (o *OrderBook) Bids(n int) []PriceLevel {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return top(o.bids, n)
+}
+
+// Asks returns up to n asks, lowest first. n <= 0 returns all of them.
+func (o *OrderBook) Asks(n int) []PriceLevel {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return top(o.asks, n)
+}
+
+// AmountAt returns the amount of the bids (OrderSideBuy) or asks (OrderSideSell) at rate.
+func (o *OrderBook) AmountAt(side string, rate decimal.Decimal) decimal.Decimal {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	for _, level := range o.side(side) {
+		if level.Rate.Equal(rate) {
+			return level.Amount
+		}
+	}
+	return decimal.Zero
+}
+
+// CumulativeAmount returns the amount of the bids (OrderSideBuy) or asks (OrderSideSell) at rate
+// or better, i.e. what an order up to rate on the other side could fill.
+func (o *OrderBook) CumulativeAmount(side string, rate decimal.Decimal) decimal.Decimal {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	total := decimal.Zero
+	better := betterRate(side)
+	for _, level := range o.side(side) {
+		if !level.Rate.Equal(rate) && !better(level.Rate, rate) {
+			break
+		}
+		total = total.Add(level.Amount)
+	}
+	return total
+}
+
+func (o *OrderBook) side(side string) []PriceLevel {
+	if side == OrderSideBuy {
+		return o.bids
+	}
+	return o.asks
+}
+
+func top(levels []PriceLevel, n int) []PriceLevel {
+	if n <= 0 || n > len(levels) {
+		n = len(levels)
+	}
+	return append([]PriceLevel{}, levels[:n]...)
+}
+
+// betterRate returns whether rate a comes before rate b on side.
+func betterRate(side string) func(a, b decimal.Decimal) bool {
+	if side == OrderSideBuy {
+		return decimal.Decimal.GreaterThan
+	}
+	return decimal.Decimal.LessThan
+}
+
+// aggregate sums the orders per rate, best rate first.
+func aggregate(side string, orders []PriceLevel) []PriceLevel {
+	levels := []PriceLevel{}
+	index := map[string]int{}
+	for _, order := range orders {
+		key := order.Rate.String()
+		if i, ok := index[key]; ok {
+			levels[i].Amount = levels[i].Amount.Add(order.Amount)
+			levels[i].Volume = levels[i].Volume.Add(order.Volume)
+			continue
+		}
+		index[key] = len(levels)
+		levels = append(levels, order)
+	}
+	better := betterRate(side)
+	sort.Slice(levels, func(i, j int) bool { return better(levels[i].Rate, levels[j].Rate) })
+	return levels
+}
+
+// merge replaces the levels down to the worst rate of update with update. An empty update keeps the levels.
+func merge(side string, levels, update []PriceLevel) []PriceLevel {
+	if len(update) == 0 {
+		return levels
+	}
+	worst := update[len(update)-1].Rate
+	better := betterRate(side)
+	merged := append([]PriceLevel{}, update...)
+	for _, level := range levels {
+		if better(worst, level.Rate) {
+			merged = append(merged, level)
+		}
+	}
+	return merged
+}
+
+func (o *OrderBook) state(event StreamStateEvent) {
+	o.mu.Lock()
+	if event.State != StreamConnected {
+		o.synced = false
+	}
+	handler := o.onState
+	o.mu.Unlock()
+	if handler != nil {
+		handler(event)
+	}
+}
+
+func (o *OrderBook) dial(ctx context.Context) (*internal.WSConn, error) {
+	o.mu.RLock()
+	id := o.id
+	o.mu.RUnlock()
+
+	if id == 0 {
+		symbols, err := o.client.GetMarketSymbolsCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range symbols {
+			if strings.EqualFold(s.Symbol, o.symbol) {
+				id = s.ID
+			}
+		}
+		if id == 0 {
+			return nil, fmt.Errorf("%w: unknown symbol %s", errNoStream, o.symbol)
+		}
+		o.mu.Lock()
+		o.id = id
+		o.mu.Unlock()
+	}
+	return o.client.dialWebSocket(ctx, fmt.Sprintf("%s%d", bookStreamPath, id))
+}
+
+// snapshot seeds the book with GetMarketBooks once connected, so that no change is missed.
+func (o *OrderBook) snapshot(ctx context.Context, conn *internal.WSConn, reconnected bool) error {
+	books, err := o.client.GetMarketBooksCtx(ctx, o.symbol, o.runner.config.bookDepth)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	o.bids = aggregate(OrderSideBuy, marketLevels(books["bids"]))
+	o.asks = aggregate(OrderSideSell, marketLevels(books["asks"]))
+	o.synced = true
+	o.mu.Unlock()
+	o.updated()
+	return nil
+}
+
+func marketLevels(orders []model.MarketBidAndAsk) []PriceLevel {
+	levels := make([]PriceLevel, 0, len(orders))
+	for _, order := range orders {
+		levels = append(levels, PriceLevel{Rate: order.Rate, Amount: order.Amount, Volume: order.Volumn})
+	}
+	return levels
+}
+
+func bookLevels(entries []model.BookEntry) []PriceLevel {
+	levels := make([]PriceLevel, 0, len(entries))
+	for _, entry := range entries {
+		levels = append(levels, PriceLevel{Rate: entry.Rate, Amount: entry.Amount, Volume: entry.Volume})
+	}
+	return levels
+}
+
+// dispatch applies a change of the book.
+func (o *OrderBook) dispatch(data []byte) {
+	event := model.BookEvent{}
+	if err := json.Unmarshal(data, &event); err != nil {
+		o.error(fmt.Errorf("decode order book %s: %w", o.symbol, err))
+		return
+	}
+
+	o.mu.Lock()
+	if event.PairingID != o.id {
+		o.mu.Unlock()
+		return
+	}
+	if event.Event != model.BookAsksChanged {
+		o.bids = merge(OrderSideBuy, o.bids, aggregate(OrderSideBuy, bookLevels(event.Bids)))
+	}
+	if event.Event != model.BookBidsChanged {
+		o.asks = merge(OrderSideSell, o.asks, aggregate(OrderSideSell, bookLevels(event.Asks)))
+	}
+	crossed := len(o.bids) > 0 && len(o.asks) > 0 && o.bids[0].Rate.GreaterThanOrEqual(o.asks[0].Rate)
+	if crossed {
+		o.synced = false
+	}
+	o.mu.Unlock()
+
+	if crossed {
+		o.runner.drop(ErrBookDesync)
+		return
+	}
+	o.updated()
+}
+
+func (o *OrderBook) updated() {
+	o.mu.RLock()
+	handler := o.onUpdate
+	o.mu.RUnlock()
+	if handler != nil {
+		handler(o)
+	}
+}
+
+func (o *OrderBook) error(err error) {
+	o.mu.RLock()
+	handler := o.onError
+	o.mu.RUnlock()
+	if handler != nil {
+		handler(err)
+	}
+}
@@ -0,0 +1,191 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/internal"
+)
+
+func levels(levels []bitkub.PriceLevel) string {
+	s := []string{}
+	for _, l := range levels {
+		s = append(s, fmt.Sprintf("%s:%s", l.Rate, l.Amount))
+	}
+	return strings.Join(s, " ")
+}
+
+func TestOrderBook(t *testing.T) {
+	var connections, snapshots int32
+	api, mux := streamServer(t, func(path string, conn *internal.WSConn) {
+		if path != "/websocket-api/orderbook/2" {
+			t.Errorf("unexpected path %s", path)
+			return
+		}
+		if atomic.AddInt32(&connections, 1) == 1 {
+			// replaces the bids from 98 up, 99 is gone and 97 is kept from the snapshot
+			conn.WriteMessage(internal.OpText, []byte(`{"event":"bidschanged","pairing_id":2,"data":[[300,100,3,0,true,false],[196,98,2,0,false,false]]}`))
+			conn.WriteMessage(internal.OpText, []byte(`{"event":"askschanged","pairing_id":1,"data":[]}`))
+			conn.WriteMessage(internal.OpText, []byte(`{"event":"tradeschanged","pairing_id":2,"data":[[[1,101,1,"BUY"]],[[300,100,3]],[[204,102,2],[103,103,1]]]}`))
+			// crossed, so the book takes a new snapshot
+			conn.WriteMessage(internal.OpText, []byte(`{"event":"bidschanged","pairing_id":2,"data":[[500,105,5]]}`))
+		}
+		conn.ReadMessage()
+	})
+	mux.HandleFunc("/api/market/symbols", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":0,"result":[{"id":1,"symbol":"THB_BTC"},{"id":2,"symbol":"THB_ETH"}]}`))
+	})
+	mux.HandleFunc("/api/market/books", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sym") != "THB_ETH" || r.URL.Query().Get("lmt") != "50" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if atomic.AddInt32(&snapshots, 1) == 1 {
+			w.Write([]byte(`{"error":0,"result":{"bids":[[1,1,99,99,1],[2,1,98,98,1],[3,1,196,98,2],[4,1,97,97,1]],"asks":[[4,1,101,101,1],[5,1,102,102,1]]}}`))
+			return
+		}
+		w.Write([]byte(`{"error":0,"result":{"bids":[[1,1,104,104,1]],"asks":[[4,1,106,106,1]]}}`))
+	})
+
+	book := api.NewOrderBook("thb_eth", fastReconnect(5), bitkub.WithBookDepth(50))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		bids, asks []string
+		disconnect error
+	)
+	book.OnUpdate(func(b *bitkub.OrderBook) {
+		bids = append(bids, levels(b.Bids(0)))
+		asks = append(asks, levels(b.Asks(0)))
+		if len(bids) == 4 {
+			cancel()
+		}
+	})
+	book.OnStateChange(func(e bitkub.StreamStateEvent) {
+		if e.State == bitkub.StreamDisconnected {
+			disconnect = e.Err
+		}
+	})
+	book.OnError(func(err error) { t.Error(err) })
+
+	if err := book.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	wantBids := []string{"99:1 98:3 97:1", "100:3 98:2 97:1", "100:3 98:2 97:1", "104:1"}
+	wantAsks := []string{"101:1 102:1", "101:1 102:1", "102:2 103:1", "106:1"}
+	if strings.Join(bids, ",") != strings.Join(wantBids, ",") || strings.Join(asks, ",") != strings.Join(wantAsks, ",") {
+		t.Fatalf("got bids %q asks %q", bids, asks)
+	}
+	if !errors.Is(disconnect, bitkub.ErrBookDesync) {
+		t.Fatalf("expected ErrBookDesync, got %v", disconnect)
+	}
+	if book.Synced() {
+		t.Fatal("expected the book not to be synced after Run returned")
+	}
+}
+
+func TestOrderBookEmptySide(t *testing.T) {
+	api, mux := streamServer(t, func(path string, conn *internal.WSConn) {
+		// an empty side keeps the levels of the snapshot
+		conn.WriteMessage(internal.OpText, []byte(`{"event":"bidschanged","pairing_id":1,"data":[]}`))
+		conn.WriteMessage(internal.OpText, []byte(`{"event":"tradeschanged","pairing_id":1,"data":[[[1,101,1,"BUY"]],[[100,100,1]],[]]}`))
+		conn.ReadMessage()
+	})
+	mux.HandleFunc("/api/market/symbols", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":0,"result":[{"id":1,"symbol":"THB_BTC"}]}`))
+	})
+	mux.HandleFunc("/api/market/books", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":0,"result":{"bids":[[1,1,99,99,1],[2,1,98,98,1]],"asks":[[3,1,101,101,1],[4,1,102,102,1]]}}`))
+	})
+
+	book := api.NewOrderBook("THB_BTC")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var bids, asks []string
+	book.OnUpdate(func(b *bitkub.OrderBook) {
+		bids = append(bids, levels(b.Bids(0)))
+		asks = append(asks, levels(b.Asks(0)))
+		if len(bids) == 3 {
+			cancel()
+		}
+	})
+	if err := book.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	wantBids := []string{"99:1 98:1", "99:1 98:1", "100:1 99:1 98:1"}
+	wantAsks := []string{"101:1 102:1", "101:1 102:1", "101:1 102:1"}
+	if strings.Join(bids, ",") != strings.Join(wantBids, ",") || strings.Join(asks, ",") != strings.Join(wantAsks, ",") {
+		t.Fatalf("got bids %q asks %q", bids, asks)
+	}
+}
+
+func TestOrderBookQueries(t *testing.T) {
+	api, mux := streamServer(t, func(path string, conn *internal.WSConn) { conn.ReadMessage() })
+	mux.HandleFunc("/api/market/symbols", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":0,"result":[{"id":1,"symbol":"THB_BTC"}]}`))
+	})
+	mux.HandleFunc("/api/market/books", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":0,"result":{"bids":[[1,1,0,99.5,1],[2,1,0,99,2],[3,1,0,99.5,0.5]],"asks":[[4,1,0,100,1],[5,1,0,101,3]]}}`))
+	})
+
+	book := api.NewOrderBook("THB_BTC")
+	if _, ok := book.Spread(); ok {
+		t.Fatal("expected no spread before Run")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	book.OnUpdate(func(b *bitkub.OrderBook) {
+		if !b.Synced() {
+			t.Error("expected the book to be synced")
+		}
+		cancel()
+	})
+	book.Run(ctx)
+
+	if bid, ok := book.BestBid(); !ok || bid.Rate.String() != "99.5" || bid.Amount.String() != "1.5" {
+		t.Fatalf("unexpected best bid %+v", bid)
+	}
+	if ask, ok := book.BestAsk(); !ok || ask.Rate.String() != "100" {
+		t.Fatalf("unexpected best ask %+v", ask)
+	}
+	if spread, ok := book.Spread(); !ok || spread.String() != "0.5" {
+		t.Fatalf("unexpected spread %s", spread)
+	}
+	if amount := book.AmountAt(bitkub.OrderSideBuy, decimal.NewFromInt(99)); amount.String() != "2" {
+		t.Fatalf("unexpected amount at 99: %s", amount)
+	}
+	if amount := book.AmountAt(bitkub.OrderSideSell, decimal.NewFromInt(99)); !amount.IsZero() {
+		t.Fatalf("unexpected ask amount at 99: %s", amount)
+	}
+	if amount := book.CumulativeAmount(bitkub.OrderSideBuy, decimal.NewFromInt(99)); amount.String() != "3.5" {
+		t.Fatalf("unexpected cumulative bids: %s", amount)
+	}
+	if amount := book.CumulativeAmount(bitkub.OrderSideSell, decimal.RequireFromString("100.5")); amount.String() != "1" {
+		t.Fatalf("unexpected cumulative asks: %s", amount)
+	}
+	if asks := levels(book.Asks(1)); asks != "100:1" {
+		t.Fatalf("unexpected asks %s", asks)
+	}
+}
+
+func TestOrderBookUnknownSymbol(t *testing.T) {
+	api, mux := streamServer(t, func(path string, conn *internal.WSConn) {})
+	mux.HandleFunc("/api/market/symbols", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":0,"result":[{"id":1,"symbol":"THB_BTC"}]}`))
+	})
+	if err := api.NewOrderBook("THB_XYZ").Run(context.Background()); err == nil || !strings.Contains(err.Error(), "THB_XYZ") {
+		t.Fatalf("expected unknown symbol error, got %v", err)
+	}
+}
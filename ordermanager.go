@@ -0,0 +1,428 @@
+package bitkub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// OrderState is the state of an order tracked by an OrderManager.
+type OrderState string
+
+const (
+	OrderStateNew             OrderState = "new"
+	OrderStatePartiallyFilled OrderState = "partially_filled"
+	OrderStateFilled          OrderState = "filled"
+	OrderStateCancelled       OrderState = "cancelled"
+	OrderStateRejected        OrderState = "rejected"
+)
+
+// Final reports whether the order cannot change anymore.
+func (s OrderState) Final() bool {
+	return s == OrderStateFilled || s == OrderStateCancelled || s == OrderStateRejected
+}
+
+// rank orders the states an order goes through, so that a stale update cannot move it back.
+func (s OrderState) rank() int {
+	switch s {
+	case OrderStateNew:
+		return 1
+	case OrderStatePartiallyFilled:
+		return 2
+	case OrderStateFilled, OrderStateCancelled, OrderStateRejected:
+		return 3
+	}
+	return 0
+}
+
+// TrackedOrder is an order as last known by an OrderManager.
+type TrackedOrder struct {
+	ID       int64
+	Hash     string
+	ClientID string
+	Symbol   string
+	Side     string
+	Type     string
+	Rate     decimal.Decimal // rate of a limit order
+	Amount   decimal.Decimal // amount as placed, see OrderRequest.Amount
+
+	State    OrderState
+	Filled   decimal.Decimal // cumulative filled amount, in the unit of Amount: of the quote asset for a buy order and of the base asset for a sell order
+	AvgPrice decimal.Decimal // average rate of the fills, weighted by the base asset, zero before the first fill
+	Fee      decimal.Decimal // fee paid so far
+	Updated  time.Time
+	Err      error // why the order was rejected
+}
+
+// OrderTransition is the change of state of an order, delivered by OrderManager.OnTransition.
+type OrderTransition struct {
+	From  OrderState // empty for an order rejected when it was placed
+	To    OrderState
+	Order TrackedOrder
+}
+
+// OrderManagerOption configures an OrderManager.
+type OrderManagerOption func(*OrderManager)
+
+// WithReconcileInterval sets how often Run reconciles the open orders with GetOrderInfo. It
+// defaults to 5s, a private stream attached with Attach delivers changes in between.
+func WithReconcileInterval(interval time.Duration) OrderManagerOption {
+	return func(m *OrderManager) {
+		m.interval = interval
+	}
+}
+
+// OrderManager tracks placed orders through their states, from new to filled, cancelled or
+// rejected. Changes are learned by polling GetOrderInfo, see Reconcile and Run, and from the
+// order updates of an attached private stream. It is safe for concurrent use, and handlers are
+// called from the goroutine which learned the change.
+type OrderManager struct {
+	client   *Client
+	interval time.Duration
+
+	mu           sync.Mutex
+	orders       map[int64]*TrackedOrder
+	onTransition func(OrderTransition)
+	onError      func(error)
+}
+
+// NewOrderManager creates a manager of the orders placed with the client.
+func (b *Client) NewOrderManager(opts ...OrderManagerOption) *OrderManager {
+	m := &OrderManager{client: b, interval: 5 * time.Second, orders: map[int64]*TrackedOrder{}}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// OnTransition sets the handler called each time an order changes state, or is filled further.
+func (m *OrderManager) OnTransition(handler func(OrderTransition)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onTransition = handler
+}
+
+// OnError sets the handler of the errors of Run.
+func (m *OrderManager) OnError(handler func(error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onError = handler
+}
+
+// Place places the order with PlaceOrder and tracks it. An order refused by Bitkub or failing its
+// validation is reported as rejected. After any other error, e.g. a timeout or a server error, the
+// order may or may not exist and is not tracked: the returned order only has its client id, with
+// which it can be looked up with FindOrder and tracked with Track.
+func (m *OrderManager) Place(ctx context.Context, req *OrderRequest) (TrackedOrder, error) {
+	if req.test {
+		return TrackedOrder{}, fmt.Errorf("a test order cannot be tracked")
+	}
+	// generate the client id here so that it is known whatever the outcome
+	placed := *req
+	placed.clientID = m.client.clientIDOf(req, false)
+	order, err := m.client.PlaceOrderCtx(ctx, &placed)
+	if err != nil {
+		var apiErr *APIError
+		if (errors.As(err, &apiErr) && !outcomeUnknown(err)) || errors.Is(err, ErrInvalidOrder) || errors.Is(err, ErrUnknownSymbol) {
+			rejected := TrackedOrder{
+				ClientID: placed.clientID,
+				Symbol:   req.symbol,
+				Side:     req.side,
+				Type:     req.bitType,
+				Rate:     req.rate,
+				Amount:   req.amount,
+				State:    OrderStateRejected,
+				Updated:  time.Now(),
+				Err:      err,
+			}
+			m.emit(OrderTransition{To: OrderStateRejected, Order: rejected})
+			return rejected, err
+		}
+		return TrackedOrder{ClientID: placed.clientID}, err
+	}
+	return m.Track(req.symbol, req.side, placed.clientID, order), nil
+}
+
+// Track tracks an order placed by other means, e.g. with PlaceBid, as new.
+func (m *OrderManager) Track(symbol, side, clientID string, order *model.Order) TrackedOrder {
+	m.mu.Lock()
+	tracked, ok := m.orders[order.ID]
+	if !ok {
+		tracked = &TrackedOrder{
+			ID:       order.ID,
+			Hash:     order.Hash,
+			ClientID: clientID,
+			Symbol:   symbol,
+			Side:     side,
+			Type:     order.Type,
+			Rate:     order.Rate,
+			Amount:   order.Amount,
+			State:    OrderStateNew,
+			Updated:  time.Now(),
+		}
+		m.orders[order.ID] = tracked
+	}
+	snapshot := *tracked
+	m.mu.Unlock()
+
+	if !ok {
+		m.emit(OrderTransition{To: OrderStateNew, Order: snapshot})
+	}
+	return snapshot
+}
+
+// Order returns the order of id.
+func (m *OrderManager) Order(id int64) (TrackedOrder, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tracked, ok := m.orders[id]
+	if !ok {
+		return TrackedOrder{}, false
+	}
+	return *tracked, true
+}
+
+// Orders returns the tracked orders by id, the open ones only unless all is set.
+func (m *OrderManager) Orders(all bool) []TrackedOrder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	orders := []TrackedOrder{}
+	for _, tracked := range m.orders {
+		if all || !tracked.State.Final() {
+			orders = append(orders, *tracked)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	return orders
+}
+
+// Forget stops tracking the orders which are final, e.g. once they were handled.
+func (m *OrderManager) Forget() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, tracked := range m.orders {
+		if tracked.State.Final() {
+			delete(m.orders, id)
+		}
+	}
+}
+
+// Cancel cancels the order of id with CancelOrder and marks it cancelled, with the fills reported by
+// GetOrderInfo since a cancelled order is no longer reconciled. When only GetOrderInfo fails, the
+// order is marked cancelled with the fills known so far and the error is returned.
+func (m *OrderManager) Cancel(ctx context.Context, id int64) error {
+	tracked, ok := m.Order(id)
+	if !ok {
+		return fmt.Errorf("order %d is not tracked", id)
+	}
+	if tracked.State.Final() {
+		return nil
+	}
+	if err := m.client.CancelOrderCtx(ctx, tracked.Symbol, tracked.Side, tracked.Hash, int(tracked.ID)); err != nil {
+		return err
+	}
+	info, err := m.client.GetOrderInfoCtx(ctx, tracked.Symbol, tracked.Side, tracked.Hash, int(tracked.ID))
+	if err == nil {
+		m.applyInfo(id, info)
+	}
+	m.update(id, func(o *TrackedOrder) { o.State = OrderStateCancelled })
+	if err != nil {
+		return fmt.Errorf("order %d cancelled, refresh its fills: %w", id, err)
+	}
+	return nil
+}
+
+// Reconcile refreshes the open orders with GetOrderInfo. An order which cannot be refreshed does
+// not hold up the others, and the errors are returned together.
+func (m *OrderManager) Reconcile(ctx context.Context) error {
+	var errs reconcileErrors
+	for _, tracked := range m.Orders(false) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		info, err := m.client.GetOrderInfoCtx(ctx, tracked.Symbol, tracked.Side, tracked.Hash, int(tracked.ID))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reconcile order %d: %w", tracked.ID, err))
+			continue
+		}
+		m.applyInfo(tracked.ID, info)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// reconcileErrors are the errors of the orders which could not be reconciled.
+type reconcileErrors []error
+
+func (e reconcileErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e reconcileErrors) Unwrap() []error {
+	return e
+}
+
+// Run reconciles the open orders every reconcile interval until ctx is done. Errors are reported
+// to the OnError handler and the next reconcile is attempted as usual.
+func (m *OrderManager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := m.Reconcile(ctx); err != nil && ctx.Err() == nil {
+			m.mu.Lock()
+			onError := m.onError
+			m.mu.Unlock()
+			if onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Attach applies the order updates of stream to the tracked orders. Updates missed while the
+// stream was reconnecting are caught up by the next reconcile.
+func (m *OrderManager) Attach(stream *PrivateStream) {
+	stream.OnOrderUpdate(m.applyEvent)
+}
+
+func (m *OrderManager) applyInfo(id int64, info *model.OrderInfo) {
+	filled, fee := decimal.Zero, decimal.Zero
+	for _, fill := range info.History {
+		filled = filled.Add(fill.Amount)
+		fee = fee.Add(fill.Fee)
+	}
+
+	state := OrderStateNew
+	switch strings.ToLower(info.Status) {
+	case "filled":
+		state = OrderStateFilled
+	case "cancelled", "canceled":
+		state = OrderStateCancelled
+	default:
+		if info.PartialFilled || filled.IsPositive() {
+			state = OrderStatePartiallyFilled
+		}
+	}
+
+	m.update(id, func(o *TrackedOrder) {
+		o.State = state
+		o.Filled, o.Fee = filled, fee
+		o.AvgPrice = averageRate(o.Side, info.History)
+	})
+}
+
+// averageRate returns the rate of the fills weighted by their amount of the base asset. The fills
+// of a buy order are amounts of the quote asset, of a sell order of the base asset.
+func averageRate(side string, fills []model.OrderInfoHistory) decimal.Decimal {
+	quote, base := decimal.Zero, decimal.Zero
+	for _, fill := range fills {
+		if !fill.Rate.IsPositive() {
+			continue
+		}
+		if side == OrderSideBuy {
+			quote, base = quote.Add(fill.Amount), base.Add(fill.Amount.Div(fill.Rate))
+		} else {
+			quote, base = quote.Add(fill.Amount.Mul(fill.Rate)), base.Add(fill.Amount)
+		}
+	}
+	if !base.IsPositive() {
+		return decimal.Zero
+	}
+	return quote.DivRound(base, 8)
+}
+
+func (m *OrderManager) applyEvent(event model.OrderUpdateEvent) {
+	m.mu.Lock()
+	var id int64
+	for _, tracked := range m.orders {
+		if strconv.FormatInt(tracked.ID, 10) == event.OrderID || (event.ClientID != "" && tracked.ClientID == event.ClientID) {
+			id = tracked.ID
+			break
+		}
+	}
+	m.mu.Unlock()
+	if id == 0 {
+		return
+	}
+
+	state := OrderStateNew
+	switch event.Status {
+	case "partial_filled":
+		state = OrderStatePartiallyFilled
+	case "filled":
+		state = OrderStateFilled
+	case "cancelled", "canceled":
+		state = OrderStateCancelled
+	}
+	m.update(id, func(o *TrackedOrder) {
+		o.State = state
+		o.Filled, o.AvgPrice, o.Fee = streamFilled(o.Side, event), event.AvgFilledPrice, event.Fee
+	})
+}
+
+// streamFilled returns the filled amount of an order update in the unit of TrackedOrder.Filled. The
+// stream reports the amount of the base asset filled, which for a buy order is turned into the
+// amount of the quote asset spent, like the fills of GetOrderInfo.
+func streamFilled(side string, event model.OrderUpdateEvent) decimal.Decimal {
+	if side == OrderSideBuy {
+		return event.FilledAmount.Mul(event.AvgFilledPrice)
+	}
+	return event.FilledAmount
+}
+
+// update applies change to a copy of the order of id, and keeps it when the order moved forward:
+// to a later state, or to the same state with more filled.
+func (m *OrderManager) update(id int64, change func(*TrackedOrder)) {
+	m.mu.Lock()
+	tracked, ok := m.orders[id]
+	if !ok || tracked.State.Final() {
+		m.mu.Unlock()
+		return
+	}
+	updated := *tracked
+	change(&updated)
+	forward := updated.State.rank() > tracked.State.rank() ||
+		(updated.State == tracked.State && updated.Filled.GreaterThan(tracked.Filled))
+	if !forward {
+		m.mu.Unlock()
+		return
+	}
+	if updated.Filled.LessThan(tracked.Filled) {
+		// keep the fills known from a fresher source
+		updated.Filled, updated.AvgPrice, updated.Fee = tracked.Filled, tracked.AvgPrice, tracked.Fee
+	}
+	updated.Updated = time.Now()
+	from := tracked.State
+	*tracked = updated
+	m.mu.Unlock()
+
+	m.emit(OrderTransition{From: from, To: updated.State, Order: updated})
+}
+
+func (m *OrderManager) emit(transition OrderTransition) {
+	m.mu.Lock()
+	handler := m.onTransition
+	m.mu.Unlock()
+	if handler != nil {
+		handler(transition)
+	}
+}
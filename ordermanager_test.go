@@ -0,0 +1,225 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+func transitionString(tr bitkub.OrderTransition) string {
+	return fmt.Sprintf("%d %s>%s %s@%s", tr.Order.ID, tr.From, tr.To, tr.Order.Filled, tr.Order.AvgPrice)
+}
+
+func TestOrderManager(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		infos = []string{
+			`{"status":"unfilled","partial_filled":false,"history":[]}`,
+			`{"status":"unfilled","partial_filled":true,"history":[{"amount":100,"rate":1000000,"fee":0.25,"id":1}]}`,
+			`{"status":"filled","partial_filled":false,"history":[{"amount":100,"rate":1000000,"fee":0.25,"id":1},{"amount":300,"rate":1200000,"fee":0.75,"id":2}]}`,
+		}
+		cancelled []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/market/place-bid", func(w http.ResponseWriter, r *http.Request) {
+		payload := payloadOf(r)
+		switch payload["amt"] {
+		case "1":
+			w.Write([]byte(`{"error":15}`))
+			return
+		case "2":
+			// may or may not have been placed
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"error":0,"result":{"id":%s,"hash":"h%s","typ":"limit","amt":400,"rat":1000000}}`, payload["client_id"], payload["client_id"])
+	})
+	mux.HandleFunc("/api/market/order-info", func(w http.ResponseWriter, r *http.Request) {
+		hash := payloadOf(r)["hash"]
+		mu.Lock()
+		defer mu.Unlock()
+		info := `{"status":"unfilled","history":[]}`
+		if hash == "h2" && len(cancelled) > 0 {
+			// filled in part since the last reconcile
+			info = `{"status":"cancelled","history":[{"amount":100,"rate":1000000,"fee":0.25,"id":3}]}`
+		}
+		if hash == "h1" {
+			info = infos[0]
+			if len(infos) > 1 {
+				infos = infos[1:]
+			}
+		}
+		fmt.Fprintf(w, `{"error":0,"result":%s}`, info)
+	})
+	mux.HandleFunc("/api/market/cancel-order", func(w http.ResponseWriter, r *http.Request) {
+		hash, _ := payloadOf(r)["hash"].(string)
+		mu.Lock()
+		cancelled = append(cancelled, hash)
+		mu.Unlock()
+		w.Write([]byte(`{"error":0}`))
+	})
+
+	manager := testClient(t, mux).NewOrderManager()
+	transitions := []string{}
+	manager.OnTransition(func(tr bitkub.OrderTransition) { transitions = append(transitions, transitionString(tr)) })
+
+	ctx := context.Background()
+	d := decimal.RequireFromString
+	if _, err := manager.Place(ctx, bitkub.BuyOrder("THB_BTC").Limit(d("1000000")).Amount(d("400")).ClientID("1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Place(ctx, bitkub.BuyOrder("THB_BTC").Limit(d("1000000")).Amount(d("400")).ClientID("2")); err != nil {
+		t.Fatal(err)
+	}
+	rejected, err := manager.Place(ctx, bitkub.BuyOrder("THB_BTC").Limit(d("1000000")).Amount(d("1")).ClientID("3"))
+	if !errors.Is(err, bitkub.ErrAmountTooLow) || rejected.State != bitkub.OrderStateRejected {
+		t.Fatalf("expected a rejected order, got %+v %v", rejected, err)
+	}
+	if unknown, err := manager.Place(ctx, bitkub.BuyOrder("THB_BTC").Limit(d("1000000")).Amount(d("2")).ClientID("4")); err == nil || unknown.State != "" || unknown.ClientID != "4" {
+		t.Fatalf("expected an untracked order, got %+v %v", unknown, err)
+	}
+
+	// order 1 gets the three infos in turn, order 2 is cancelled after the first
+	if err := manager.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Cancel(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := manager.Reconcile(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"1 >new 0@0",
+		"2 >new 0@0",
+		"0 >rejected 0@0",
+		"2 new>cancelled 100@1000000",
+		"1 new>partially_filled 100@1000000",
+		// 400 THB bought 0.00035 BTC
+		"1 partially_filled>filled 400@1142857.14285714",
+	}
+	if strings.Join(transitions, ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected transitions %v", transitions)
+	}
+	if order, _ := manager.Order(1); order.Fee.String() != "1" || order.Hash != "h1" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+	if open := manager.Orders(false); len(open) != 0 {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+	if strings.Join(cancelled, ",") != "h2" {
+		t.Fatalf("unexpected cancels %v", cancelled)
+	}
+	manager.Forget()
+	if all := manager.Orders(true); len(all) != 0 {
+		t.Fatalf("unexpected orders %+v", all)
+	}
+}
+
+func TestOrderManagerGeneratedClientID(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if payloadOf(r)["amt"] == "1" {
+			w.Write([]byte(`{"error":15}`))
+			return
+		}
+		w.Write([]byte(`{"error":0,"result":{"id":1,"hash":"h1","typ":"limit","amt":400,"rat":1000000}}`))
+	})
+
+	generated := 0
+	generator := bitkub.WithClientIDGenerator(func() string {
+		generated++
+		return fmt.Sprintf("gen-%d", generated)
+	})
+	manager := testClient(t, handler, generator).NewOrderManager()
+
+	ctx := context.Background()
+	d := decimal.RequireFromString
+	placed, err := manager.Place(ctx, bitkub.BuyOrder("THB_BTC").Limit(d("1000000")).Amount(d("400")))
+	if err != nil || placed.ClientID != "gen-1" {
+		t.Fatalf("unexpected order %+v %v", placed, err)
+	}
+	if tracked, _ := manager.Order(1); tracked.ClientID != "gen-1" {
+		t.Fatalf("unexpected tracked order %+v", tracked)
+	}
+	rejected, err := manager.Place(ctx, bitkub.BuyOrder("THB_BTC").Limit(d("1000000")).Amount(d("1")))
+	if err == nil || rejected.State != bitkub.OrderStateRejected || rejected.ClientID != "gen-2" || generated != 2 {
+		t.Fatalf("unexpected rejected order %+v %v", rejected, err)
+	}
+}
+
+func TestOrderManagerStream(t *testing.T) {
+	api := privateStreamServer(t, func(conn *internal.WSConn) {
+		acceptAuth(t, conn)
+		readEvent(conn)
+		readEvent(conn)
+		for _, update := range []string{
+			// amounts of the base asset
+			`{"order_id":"1001","status":"partial_filled","filled_amount":"0.004","avg_filled_price":"2000000","fee":"20"}`,
+			`{"order_id":"9999","status":"filled","filled_amount":"1"}`,
+			`{"order_id":"1001","status":"new","filled_amount":"0"}`,
+			`{"order_id":"1001","client_id":"my-1","status":"filled","filled_amount":"0.01","avg_filled_price":"2000001","fee":"50"}`,
+		} {
+			conn.WriteMessage(internal.OpText, []byte(`{"event":"order_update","data":`+update+`}`))
+		}
+		conn.ReadMessage()
+	})
+
+	manager := api.NewOrderManager()
+	manager.Track("THB_BTC", bitkub.OrderSideBuy, "my-1", &model.Order{ID: 1001, Amount: decimal.RequireFromString("20000.01")})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	transitions := []string{}
+	manager.OnTransition(func(tr bitkub.OrderTransition) {
+		transitions = append(transitions, transitionString(tr))
+		if tr.To.Final() {
+			cancel()
+		}
+	})
+	stream := api.NewPrivateStream()
+	manager.Attach(stream)
+	stream.Run(ctx)
+
+	// the buy order is tracked in the quote asset, like by GetOrderInfo
+	expected := "1001 new>partially_filled 8000@2000000,1001 partially_filled>filled 20000.01@2000001"
+	if strings.Join(transitions, ",") != expected {
+		t.Fatalf("unexpected transitions %v", transitions)
+	}
+}
+
+func TestOrderManagerReconcileErrors(t *testing.T) {
+	manager := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if payloadOf(r)["hash"] == "h1" {
+			w.Write([]byte(`{"error":24}`))
+			return
+		}
+		w.Write([]byte(`{"error":0,"result":{"status":"filled","history":[{"amount":400,"rate":1000000,"fee":1,"id":1}]}}`))
+	})).NewOrderManager()
+	for id := int64(1); id <= 3; id++ {
+		manager.Track("THB_BTC", bitkub.OrderSideBuy, "", &model.Order{ID: id, Hash: fmt.Sprintf("h%d", id)})
+	}
+
+	// the failing order 1 does not hold up the others
+	err := manager.Reconcile(context.Background())
+	var apiErr *bitkub.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 24 || !strings.Contains(err.Error(), "order 1") {
+		t.Fatalf("expected the error of order 1, got %v", err)
+	}
+	open := manager.Orders(false)
+	if len(open) != 1 || open[0].ID != 1 {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+}
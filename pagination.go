@@ -0,0 +1,468 @@
+package bitkub
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// ErrTooManyPages is returned by the All helpers when more pages than the cap are available.
+var ErrTooManyPages = errors.New("too many pages")
+
+const (
+	defaultPageSize = 100
+	defaultMaxPages = 100
+)
+
+type pageConfig struct {
+	limit     int
+	startPage int
+	since     time.Time
+	maxPages  int
+}
+
+// PageOption configures a paged iterator or All helper.
+type PageOption func(*pageConfig)
+
+// WithPageSize sets how many items are requested per page. It defaults to 100.
+func WithPageSize(limit int) PageOption {
+	return func(c *pageConfig) {
+		c.limit = limit
+	}
+}
+
+// WithStartPage starts at the given page instead of the first one, e.g. to resume an iteration.
+func WithStartPage(page int) PageOption {
+	return func(c *pageConfig) {
+		c.startPage = page
+	}
+}
+
+// WithSince stops at the first item older than since. Items come newest first.
+func WithSince(since time.Time) PageOption {
+	return func(c *pageConfig) {
+		c.since = since
+	}
+}
+
+// WithMaxPages caps the number of pages fetched. Iterators are not capped by default and the
+// All helpers fetch up to 100 pages. Iteration fails with ErrTooManyPages beyond the cap.
+func WithMaxPages(n int) PageOption {
+	return func(c *pageConfig) {
+		c.maxPages = n
+	}
+}
+
+func newPageConfig(opts []PageOption, maxPages int) pageConfig {
+	c := pageConfig{limit: defaultPageSize, startPage: 1, maxPages: maxPages}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// unixTime converts a timestamp in seconds or milliseconds.
+func unixTime(ts int64) time.Time {
+	if ts > 1e12 {
+		return time.Unix(0, ts*int64(time.Millisecond))
+	}
+	return time.Unix(ts, 0)
+}
+
+func sinceUnix(since time.Time) int64 {
+	if since.IsZero() {
+		return 0
+	}
+	return since.Unix()
+}
+
+// pager fetches the pages of an endpoint lazily for a typed iterator.
+type pager struct {
+	ctx    context.Context
+	config pageConfig
+
+	// fetch requests page and returns the number of items and the last page, 0 when unknown.
+	fetch func(ctx context.Context, page, limit int) (int, int, error)
+	// timestamp returns the time of item i of the current page.
+	timestamp func(i int) int64
+
+	page  int // next page to fetch
+	pages int // pages fetched
+	i, n  int // current item and number of items of the current page
+	done  bool
+	err   error
+}
+
+func newPager(ctx context.Context, config pageConfig) pager {
+	return pager{ctx: ctx, config: config, page: config.startPage, i: -1}
+}
+
+func (p *pager) next() bool {
+	if p.err != nil {
+		return false
+	}
+	p.i++
+	for p.i >= p.n {
+		if p.done {
+			return false
+		}
+		if p.config.maxPages > 0 && p.pages >= p.config.maxPages {
+			p.err = ErrTooManyPages
+			return false
+		}
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+
+		n, last, err := p.fetch(p.ctx, p.page, p.config.limit)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.pages++
+		p.i, p.n = 0, n
+		p.done = n == 0 || (last > 0 && p.page >= last)
+		p.page++
+	}
+
+	if !p.config.since.IsZero() && unixTime(p.timestamp(p.i)).Before(p.config.since) {
+		p.done, p.n = true, 0
+		return false
+	}
+	return true
+}
+
+// Page returns the page of the current item, e.g. to resume with WithStartPage.
+func (p *pager) Page() int {
+	return p.page - 1
+}
+
+// Err returns the error which stopped the iteration, nil when all items were read.
+func (p *pager) Err() error {
+	return p.err
+}
+
+// CryptoAddressIterator iterates over the crypto addresses, see IterCryptoAddresses.
+type CryptoAddressIterator struct {
+	pager
+	items []model.CryptoAddress
+}
+
+// Next fetches the next item, and the next page when needed. It returns false at the end or on error, see Err.
+func (it *CryptoAddressIterator) Next() bool {
+	return it.next()
+}
+
+// Item returns the current item.
+func (it *CryptoAddressIterator) Item() model.CryptoAddress {
+	return it.items[it.i]
+}
+
+// IterCryptoAddresses returns an iterator over the crypto addresses, fetching pages lazily.
+func (b *Client) IterCryptoAddresses(ctx context.Context, opts ...PageOption) *CryptoAddressIterator {
+	return b.iterCryptoAddresses(ctx, newPageConfig(opts, 0))
+}
+
+// AllCryptoAddresses returns all the crypto addresses, up to 100 pages unless set otherwise with WithMaxPages.
+// On error it returns the items fetched so far.
+func (b *Client) AllCryptoAddresses(ctx context.Context, opts ...PageOption) ([]model.CryptoAddress, error) {
+	it := b.iterCryptoAddresses(ctx, newPageConfig(opts, defaultMaxPages))
+	items := []model.CryptoAddress{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+func (b *Client) iterCryptoAddresses(ctx context.Context, config pageConfig) *CryptoAddressIterator {
+	it := &CryptoAddressIterator{pager: newPager(ctx, config)}
+	it.fetch = func(ctx context.Context, page, limit int) (int, int, error) {
+		items, pagination, err := b.GetCryptoAddressesCtx(ctx, page, limit)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.items = items
+		return len(items), pagination.Last, nil
+	}
+	it.timestamp = func(i int) int64 { return it.items[i].Timestamp }
+	return it
+}
+
+// CryptoDepositIterator iterates over the crypto deposits, newest first, see IterCryptoDepositHistory.
+type CryptoDepositIterator struct {
+	pager
+	items []model.CryptoDeposit
+}
+
+// Next fetches the next item, and the next page when needed. It returns false at the end or on error, see Err.
+func (it *CryptoDepositIterator) Next() bool {
+	return it.next()
+}
+
+// Item returns the current item.
+func (it *CryptoDepositIterator) Item() model.CryptoDeposit {
+	return it.items[it.i]
+}
+
+// IterCryptoDepositHistory returns an iterator over the crypto deposits, newest first, fetching pages lazily.
+func (b *Client) IterCryptoDepositHistory(ctx context.Context, opts ...PageOption) *CryptoDepositIterator {
+	return b.iterCryptoDepositHistory(ctx, newPageConfig(opts, 0))
+}
+
+// AllCryptoDepositHistory returns all the crypto deposits, newest first, up to 100 pages unless set otherwise with WithMaxPages.
+// On error it returns the items fetched so far.
+func (b *Client) AllCryptoDepositHistory(ctx context.Context, opts ...PageOption) ([]model.CryptoDeposit, error) {
+	it := b.iterCryptoDepositHistory(ctx, newPageConfig(opts, defaultMaxPages))
+	items := []model.CryptoDeposit{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+func (b *Client) iterCryptoDepositHistory(ctx context.Context, config pageConfig) *CryptoDepositIterator {
+	it := &CryptoDepositIterator{pager: newPager(ctx, config)}
+	it.fetch = func(ctx context.Context, page, limit int) (int, int, error) {
+		items, pagination, err := b.GetCryptoDepositHistoryCtx(ctx, page, limit)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.items = items
+		return len(items), pagination.Last, nil
+	}
+	it.timestamp = func(i int) int64 { return it.items[i].Timestamp }
+	return it
+}
+
+// CryptoWithdrawIterator iterates over the crypto withdrawals, newest first, see IterCryptoWithdrawHistory.
+type CryptoWithdrawIterator struct {
+	pager
+	items []model.CryptoWithdraw
+}
+
+// Next fetches the next item, and the next page when needed. It returns false at the end or on error, see Err.
+func (it *CryptoWithdrawIterator) Next() bool {
+	return it.next()
+}
+
+// Item returns the current item.
+func (it *CryptoWithdrawIterator) Item() model.CryptoWithdraw {
+	return it.items[it.i]
+}
+
+// IterCryptoWithdrawHistory returns an iterator over the crypto withdrawals, newest first, fetching pages lazily.
+func (b *Client) IterCryptoWithdrawHistory(ctx context.Context, opts ...PageOption) *CryptoWithdrawIterator {
+	return b.iterCryptoWithdrawHistory(ctx, newPageConfig(opts, 0))
+}
+
+// AllCryptoWithdrawHistory returns all the crypto withdrawals, newest first, up to 100 pages unless set otherwise with WithMaxPages.
+// On error it returns the items fetched so far.
+func (b *Client) AllCryptoWithdrawHistory(ctx context.Context, opts ...PageOption) ([]model.CryptoWithdraw, error) {
+	it := b.iterCryptoWithdrawHistory(ctx, newPageConfig(opts, defaultMaxPages))
+	items := []model.CryptoWithdraw{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+func (b *Client) iterCryptoWithdrawHistory(ctx context.Context, config pageConfig) *CryptoWithdrawIterator {
+	it := &CryptoWithdrawIterator{pager: newPager(ctx, config)}
+	it.fetch = func(ctx context.Context, page, limit int) (int, int, error) {
+		items, pagination, err := b.GetCryptoWithdrawHistoryCtx(ctx, page, limit)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.items = items
+		return len(items), pagination.Last, nil
+	}
+	it.timestamp = func(i int) int64 { return it.items[i].Timestamp }
+	return it
+}
+
+// BankAccountIterator iterates over the approved bank accounts, see IterBankAccounts.
+type BankAccountIterator struct {
+	pager
+	items []model.BankAccount
+}
+
+// Next fetches the next item, and the next page when needed. It returns false at the end or on error, see Err.
+func (it *BankAccountIterator) Next() bool {
+	return it.next()
+}
+
+// Item returns the current item.
+func (it *BankAccountIterator) Item() model.BankAccount {
+	return it.items[it.i]
+}
+
+// IterBankAccounts returns an iterator over the approved bank accounts, fetching pages lazily.
+func (b *Client) IterBankAccounts(ctx context.Context, opts ...PageOption) *BankAccountIterator {
+	return b.iterBankAccounts(ctx, newPageConfig(opts, 0))
+}
+
+// AllBankAccounts returns all the approved bank accounts, up to 100 pages unless set otherwise with WithMaxPages.
+// On error it returns the items fetched so far.
+func (b *Client) AllBankAccounts(ctx context.Context, opts ...PageOption) ([]model.BankAccount, error) {
+	it := b.iterBankAccounts(ctx, newPageConfig(opts, defaultMaxPages))
+	items := []model.BankAccount{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+func (b *Client) iterBankAccounts(ctx context.Context, config pageConfig) *BankAccountIterator {
+	it := &BankAccountIterator{pager: newPager(ctx, config)}
+	it.fetch = func(ctx context.Context, page, limit int) (int, int, error) {
+		items, pagination, err := b.GetBankAccountsCtx(ctx, page, limit)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.items = items
+		return len(items), pagination.Last, nil
+	}
+	it.timestamp = func(i int) int64 { return it.items[i].Timestamp }
+	return it
+}
+
+// FiatDepositIterator iterates over the fiat deposits, newest first, see IterFiatDepositHistory.
+type FiatDepositIterator struct {
+	pager
+	items []model.FiatDeposit
+}
+
+// Next fetches the next item, and the next page when needed. It returns false at the end or on error, see Err.
+func (it *FiatDepositIterator) Next() bool {
+	return it.next()
+}
+
+// Item returns the current item.
+func (it *FiatDepositIterator) Item() model.FiatDeposit {
+	return it.items[it.i]
+}
+
+// IterFiatDepositHistory returns an iterator over the fiat deposits, newest first, fetching pages lazily.
+func (b *Client) IterFiatDepositHistory(ctx context.Context, opts ...PageOption) *FiatDepositIterator {
+	return b.iterFiatDepositHistory(ctx, newPageConfig(opts, 0))
+}
+
+// AllFiatDepositHistory returns all the fiat deposits, newest first, up to 100 pages unless set otherwise with WithMaxPages.
+// On error it returns the items fetched so far.
+func (b *Client) AllFiatDepositHistory(ctx context.Context, opts ...PageOption) ([]model.FiatDeposit, error) {
+	it := b.iterFiatDepositHistory(ctx, newPageConfig(opts, defaultMaxPages))
+	items := []model.FiatDeposit{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+func (b *Client) iterFiatDepositHistory(ctx context.Context, config pageConfig) *FiatDepositIterator {
+	it := &FiatDepositIterator{pager: newPager(ctx, config)}
+	it.fetch = func(ctx context.Context, page, limit int) (int, int, error) {
+		items, pagination, err := b.GetFiatDepositHistoryCtx(ctx, page, limit)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.items = items
+		return len(items), pagination.Last, nil
+	}
+	it.timestamp = func(i int) int64 { return it.items[i].Timestamp }
+	return it
+}
+
+// FiatWithdrawIterator iterates over the fiat withdrawals, newest first, see IterFiatWithdrawHistory.
+type FiatWithdrawIterator struct {
+	pager
+	items []model.FiatWithdraw
+}
+
+// Next fetches the next item, and the next page when needed. It returns false at the end or on error, see Err.
+func (it *FiatWithdrawIterator) Next() bool {
+	return it.next()
+}
+
+// Item returns the current item.
+func (it *FiatWithdrawIterator) Item() model.FiatWithdraw {
+	return it.items[it.i]
+}
+
+// IterFiatWithdrawHistory returns an iterator over the fiat withdrawals, newest first, fetching pages lazily.
+func (b *Client) IterFiatWithdrawHistory(ctx context.Context, opts ...PageOption) *FiatWithdrawIterator {
+	return b.iterFiatWithdrawHistory(ctx, newPageConfig(opts, 0))
+}
+
+// AllFiatWithdrawHistory returns all the fiat withdrawals, newest first, up to 100 pages unless set otherwise with WithMaxPages.
+// On error it returns the items fetched so far.
+func (b *Client) AllFiatWithdrawHistory(ctx context.Context, opts ...PageOption) ([]model.FiatWithdraw, error) {
+	it := b.iterFiatWithdrawHistory(ctx, newPageConfig(opts, defaultMaxPages))
+	items := []model.FiatWithdraw{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+func (b *Client) iterFiatWithdrawHistory(ctx context.Context, config pageConfig) *FiatWithdrawIterator {
+	it := &FiatWithdrawIterator{pager: newPager(ctx, config)}
+	it.fetch = func(ctx context.Context, page, limit int) (int, int, error) {
+		items, pagination, err := b.GetFiatWithdrawHistoryCtx(ctx, page, limit)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.items = items
+		return len(items), pagination.Last, nil
+	}
+	it.timestamp = func(i int) int64 { return it.items[i].Timestamp }
+	return it
+}
+
+// OrderHistoryIterator iterates over the matched orders of symbol, newest first, see IterOrderHistory.
+type OrderHistoryIterator struct {
+	pager
+	items []model.OrderHistory
+}
+
+// Next fetches the next item, and the next page when needed. It returns false at the end or on error, see Err.
+func (it *OrderHistoryIterator) Next() bool {
+	return it.next()
+}
+
+// Item returns the current item.
+func (it *OrderHistoryIterator) Item() model.OrderHistory {
+	return it.items[it.i]
+}
+
+// IterOrderHistory returns an iterator over the matched orders of symbol, newest first, fetching pages lazily.
+func (b *Client) IterOrderHistory(ctx context.Context, symbol string, opts ...PageOption) *OrderHistoryIterator {
+	return b.iterOrderHistory(ctx, symbol, newPageConfig(opts, 0))
+}
+
+// AllOrderHistory returns all the matched orders of symbol, newest first, up to 100 pages unless set otherwise with WithMaxPages.
+// On error it returns the items fetched so far.
+func (b *Client) AllOrderHistory(ctx context.Context, symbol string, opts ...PageOption) ([]model.OrderHistory, error) {
+	it := b.iterOrderHistory(ctx, symbol, newPageConfig(opts, defaultMaxPages))
+	items := []model.OrderHistory{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+func (b *Client) iterOrderHistory(ctx context.Context, symbol string, config pageConfig) *OrderHistoryIterator {
+	it := &OrderHistoryIterator{pager: newPager(ctx, config)}
+	it.fetch = func(ctx context.Context, page, limit int) (int, int, error) {
+		items, pagination, err := b.GetOrderHistoryCtx(ctx, symbol, page, limit, sinceUnix(config.since), 0)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.items = items
+		return len(items), pagination.Last, nil
+	}
+	it.timestamp = func(i int) int64 { return it.items[i].Timestamp }
+	return it
+}
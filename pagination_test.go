@@ -0,0 +1,92 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+)
+
+// pagedServer serves 3 pages of 2 deposits, newest first, with times 6 down to 1, and
+// records the payload of the requests.
+func pagedServer(t *testing.T) (*bitkub.Client, *[]map[string]interface{}) {
+	var requests []map[string]interface{}
+	api := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := payloadOf(r)
+		requests = append(requests, payload)
+
+		page := int(payload["p"].(float64))
+		items := []string{}
+		for ts := 8 - 2*page; ts > 6-2*page && ts > 0; ts-- {
+			if r.URL.Path == "/api/market/my-order-history" {
+				items = append(items, fmt.Sprintf(`{"txn_id":"%d","ts":%d}`, ts, ts))
+			} else {
+				items = append(items, fmt.Sprintf(`{"hash":"%d","time":%d}`, ts, ts))
+			}
+		}
+		fmt.Fprintf(w, `{"error":0,"result":[%s],"pagination":{"page":%d,"last":3}}`, strings.Join(items, ","), page)
+	}))
+	return api, &requests
+}
+
+func TestIterCryptoDepositHistory(t *testing.T) {
+	api, requests := pagedServer(t)
+
+	it := api.IterCryptoDepositHistory(context.Background(), bitkub.WithPageSize(2))
+	hashes := []string{}
+	for it.Next() {
+		hashes = append(hashes, it.Item().Hash)
+		if len(hashes) == 1 && len(*requests) != 1 {
+			t.Fatal("expected pages to be fetched lazily")
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(hashes, ",") != "6,5,4,3,2,1" || len(*requests) != 3 || it.Page() != 3 {
+		t.Fatalf("unexpected deposits %v after %d requests, page %d", hashes, len(*requests), it.Page())
+	}
+	if (*requests)[1]["lmt"] != float64(2) {
+		t.Fatalf("unexpected payload %v", (*requests)[1])
+	}
+}
+
+func TestIterSince(t *testing.T) {
+	api, requests := pagedServer(t)
+
+	orders, err := api.AllOrderHistory(context.Background(), "THB_BTC", bitkub.WithPageSize(2), bitkub.WithSince(time.Unix(4, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 3 || orders[2].TxnID != "4" || len(*requests) != 2 {
+		t.Fatalf("unexpected orders %+v after %d requests", orders, len(*requests))
+	}
+	if (*requests)[0]["start"] != float64(4) || (*requests)[0]["sym"] != "THB_BTC" {
+		t.Fatalf("unexpected payload %v", (*requests)[0])
+	}
+}
+
+func TestAllPagesCap(t *testing.T) {
+	api, _ := pagedServer(t)
+
+	deposits, err := api.AllCryptoDepositHistory(context.Background(), bitkub.WithPageSize(2), bitkub.WithMaxPages(2))
+	if !errors.Is(err, bitkub.ErrTooManyPages) || len(deposits) != 4 {
+		t.Fatalf("expected ErrTooManyPages after 4 deposits, got %d, %v", len(deposits), err)
+	}
+
+	resumed, err := api.AllCryptoDepositHistory(context.Background(), bitkub.WithPageSize(2), bitkub.WithStartPage(3))
+	if err != nil || len(resumed) != 2 || resumed[0].Hash != "2" {
+		t.Fatalf("unexpected resumed deposits %+v, %v", resumed, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := api.AllBankAccounts(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
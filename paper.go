@@ -0,0 +1,607 @@
+package bitkub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// defaultPaperFee is the trading fee of Bitkub, 0.25%.
+var defaultPaperFee = decimal.New(25, -4)
+
+// paperPrecision is the number of decimal places of the amounts of the base asset computed by PaperTrader.
+const paperPrecision = 8
+
+var _ Trader = (*PaperTrader)(nil)
+
+// PaperOption configures a PaperTrader.
+type PaperOption func(*PaperTrader)
+
+// WithPaperFees sets the fees of the orders resting in the book and of those taking from it, as a
+// fraction of the quote asset. Both default to 0.0025.
+func WithPaperFees(maker, taker decimal.Decimal) PaperOption {
+	return func(p *PaperTrader) {
+		p.makerFee, p.takerFee = maker, taker
+	}
+}
+
+// WithPaperBookDepth sets how many levels of GetMarketBooks orders are matched against. It defaults to 50.
+func WithPaperBookDepth(limit int) PaperOption {
+	return func(p *PaperTrader) {
+		p.depth = limit
+	}
+}
+
+// WithMatchInterval sets how often Run matches the open orders against the books. It defaults to 5s.
+func WithMatchInterval(interval time.Duration) PaperOption {
+	return func(p *PaperTrader) {
+		p.interval = interval
+	}
+}
+
+// paperOrder is an order of a PaperTrader.
+type paperOrder struct {
+	id        int
+	hash      string
+	clientID  string
+	symbol    Symbol
+	side      string
+	bitType   string
+	rate      decimal.Decimal
+	amount    decimal.Decimal // amount as placed, of the quote asset for a buy order and of the base asset otherwise
+	remaining decimal.Decimal // amount not filled yet, in the unit of amount
+	fee       decimal.Decimal // fees paid so far
+	receive   decimal.Decimal // amount received so far
+	ts        int64
+}
+
+type paperFill struct {
+	symbol Symbol
+	model.OrderHistory
+}
+
+// PaperTrader simulates the trading methods of Client against live market data, with a virtual
+// balance sheet. An order first takes the levels of GetMarketBooks it crosses, as a taker, and a
+// limit order rests with what is left. Resting orders are filled at their rate, as makers, by
+// Match when the book crosses them, or by AddTrade and AddTradeEvent when a trade does, within the
+// amount of the book or trade. Fees are charged in the quote asset.
+//
+// The liquidity taken is not removed from the live data, so orders placed before the next book may
+// take the same levels again. It is safe for concurrent use.
+type PaperTrader struct {
+	client   *Client
+	makerFee decimal.Decimal
+	takerFee decimal.Decimal
+	depth    int
+	interval time.Duration
+
+	mu       sync.Mutex
+	balances map[string]*model.Balance
+	open     map[int]*paperOrder
+	fills    []paperFill // oldest first
+	lastID   int
+	lastTxn  int
+	onError  func(error)
+}
+
+// NewPaperTrader creates a paper trader with the available balances by currency, e.g. THB, which
+// uses the market data and symbol registry of the client.
+func (b *Client) NewPaperTrader(balances map[string]decimal.Decimal, opts ...PaperOption) *PaperTrader {
+	p := &PaperTrader{
+		client:   b,
+		makerFee: defaultPaperFee,
+		takerFee: defaultPaperFee,
+		depth:    50,
+		interval: 5 * time.Second,
+		balances: map[string]*model.Balance{},
+		open:     map[int]*paperOrder{},
+	}
+	for currency, amount := range balances {
+		p.balances[strings.ToUpper(currency)] = &model.Balance{Available: amount, Reserved: decimal.Zero}
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// OnError sets the handler of the errors of Run.
+func (p *PaperTrader) OnError(handler func(error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onError = handler
+}
+
+// PlaceOrder is like Client.PlaceOrder.
+func (p *PaperTrader) PlaceOrder(req *OrderRequest) (*model.Order, error) {
+	return p.PlaceOrderCtx(context.Background(), req)
+}
+
+// PlaceOrderCtx is like PlaceOrder but carries ctx for cancellation and deadlines.
+func (p *PaperTrader) PlaceOrderCtx(ctx context.Context, req *OrderRequest) (*model.Order, error) {
+	order, err := req.resolve(ctx, p.client, false, p.GetBalancesCtx)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := ParseSymbol(order.symbol)
+	if err != nil {
+		return nil, err
+	}
+	book, err := p.client.GetMarketBooksCtx(ctx, symbol.Legacy(), p.depth)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/api/market/place-bid"
+	levels := sortedLevels(book["asks"], false)
+	if req.side == OrderSideSell {
+		path = "/api/market/place-ask"
+		levels = sortedLevels(book["bids"], true)
+	}
+
+	amount := order.amount
+	if req.side == OrderSideSell && order.byQuote {
+		path = "/api/market/place-ask-by-fiat"
+		rate := order.rate
+		if order.bitType == OrderTypeMarket {
+			if len(levels) == 0 || !levels[0].Rate.IsPositive() {
+				return nil, paperError(14, path)
+			}
+			rate = levels[0].Rate
+		}
+		amount = amount.DivRound(rate, paperPrecision)
+	}
+	if req.test {
+		return &model.Order{Type: order.bitType, Amount: amount, Rate: order.rate, Timestamp: time.Now().Unix(), ClientID: order.clientID}, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	spent := p.balance(symbol.Base)
+	if req.side == OrderSideBuy {
+		spent = p.balance(symbol.Quote)
+	}
+	if spent.Available.LessThan(amount) {
+		return nil, paperError(18, path)
+	}
+	spent.Available = spent.Available.Sub(amount)
+	spent.Reserved = spent.Reserved.Add(amount)
+
+	p.lastID++
+	o := &paperOrder{
+		id:        p.lastID,
+		hash:      fmt.Sprintf("paper%d", p.lastID),
+		clientID:  order.clientID,
+		symbol:    symbol,
+		side:      req.side,
+		bitType:   order.bitType,
+		rate:      order.rate,
+		amount:    amount,
+		remaining: amount,
+		fee:       decimal.Zero,
+		receive:   decimal.Zero,
+		ts:        time.Now().Unix(),
+	}
+	for _, level := range levels {
+		if !o.remaining.IsPositive() || (o.bitType == OrderTypeLimit && !o.crossed(level.Rate)) {
+			break
+		}
+		p.fill(o, level.Rate, level.Amount, false)
+	}
+	if o.remaining.IsPositive() {
+		if o.bitType == OrderTypeMarket {
+			// what a market order cannot take from the book is cancelled
+			p.release(o)
+		} else {
+			p.open[o.id] = o
+		}
+	}
+	return o.order(), nil
+}
+
+// PlaceBid is like Client.PlaceBid.
+func (p *PaperTrader) PlaceBid(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return p.PlaceBidCtx(context.Background(), symbol, bitType, amount, rate, clientID...)
+}
+
+// PlaceBidCtx is like PlaceBid but carries ctx for cancellation and deadlines.
+func (p *PaperTrader) PlaceBidCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return p.PlaceOrderCtx(ctx, newOrderRequest(OrderSideBuy, symbol, bitType, amount, rate, clientID))
+}
+
+// PlaceAsk is like Client.PlaceAsk.
+func (p *PaperTrader) PlaceAsk(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return p.PlaceAskCtx(context.Background(), symbol, bitType, amount, rate, clientID...)
+}
+
+// PlaceAskCtx is like PlaceAsk but carries ctx for cancellation and deadlines.
+func (p *PaperTrader) PlaceAskCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error) {
+	return p.PlaceOrderCtx(ctx, newOrderRequest(OrderSideSell, symbol, bitType, amount, rate, clientID))
+}
+
+// CancelOrder is like Client.CancelOrder.
+func (p *PaperTrader) CancelOrder(symbol, side, hash string, id int) error {
+	return p.CancelOrderCtx(context.Background(), symbol, side, hash, id)
+}
+
+// CancelOrderCtx is like CancelOrder but carries ctx for cancellation and deadlines.
+func (p *PaperTrader) CancelOrderCtx(ctx context.Context, symbol, side, hash string, id int) error {
+	if _, err := orderRefPayload(symbol, side, hash, id); err != nil {
+		return err
+	}
+	// like Bitkub, an order given by hash is found whatever the symbol
+	var s Symbol
+	if hash == "" {
+		var err error
+		if s, err = ParseSymbol(symbol); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, o := range p.open {
+		if (hash != "" && o.hash == hash) || (hash == "" && o.id == id && o.side == side && o.symbol == s) {
+			p.release(o)
+			delete(p.open, o.id)
+			return nil
+		}
+	}
+	return paperError(21, "/api/market/cancel-order")
+}
+
+// GetOpenOrder is like Client.GetOpenOrder.
+func (p *PaperTrader) GetOpenOrder(symbol string) ([]model.OpenOrder, error) {
+	return p.GetOpenOrderCtx(context.Background(), symbol)
+}
+
+// GetOpenOrderCtx is like GetOpenOrder but carries ctx for cancellation and deadlines.
+func (p *PaperTrader) GetOpenOrderCtx(ctx context.Context, symbol string) ([]model.OpenOrder, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is empty")
+	}
+	s, err := ParseSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	orders := []model.OpenOrder{}
+	for _, o := range p.sortedOpen(s) {
+		orders = append(orders, model.OpenOrder{
+			ID:        o.id,
+			Hash:      o.hash,
+			Side:      o.side,
+			Type:      o.bitType,
+			Rate:      o.rate,
+			Fee:       o.fee,
+			Credit:    decimal.Zero,
+			Amount:    o.remaining,
+			Receive:   o.expected(),
+			Timestamp: o.ts,
+		})
+	}
+	return orders, nil
+}
+
+// GetBalances is like Client.GetBalances.
+func (p *PaperTrader) GetBalances() (map[string]model.Balance, error) {
+	return p.GetBalancesCtx(context.Background())
+}
+
+// GetBalancesCtx is like GetBalances but carries ctx for cancellation and deadlines.
+func (p *PaperTrader) GetBalancesCtx(ctx context.Context) (map[string]model.Balance, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	balances := make(map[string]model.Balance, len(p.balances))
+	for currency, balance := range p.balances {
+		balances[currency] = *balance
+	}
+	return balances, nil
+}
+
+// GetOrderHistory is like Client.GetOrderHistory.
+func (p *PaperTrader) GetOrderHistory(symbol string, page, limit int, start, end int64) ([]model.OrderHistory, *model.OrderHistoryPagination, error) {
+	return p.GetOrderHistoryCtx(context.Background(), symbol, page, limit, start, end)
+}
+
+// GetOrderHistoryCtx is like GetOrderHistory but carries ctx for cancellation and deadlines.
+func (p *PaperTrader) GetOrderHistoryCtx(ctx context.Context, symbol string, page, limit int, start, end int64) ([]model.OrderHistory, *model.OrderHistoryPagination, error) {
+	if symbol == "" {
+		return nil, nil, fmt.Errorf("symbol is empty")
+	}
+	s, err := ParseSymbol(symbol)
+	if err != nil {
+		return nil, nil, err
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	history := []model.OrderHistory{}
+	for i := len(p.fills) - 1; i >= 0; i-- {
+		fill := p.fills[i]
+		if fill.symbol == s && (start <= 0 || fill.Timestamp >= start) && (end <= 0 || fill.Timestamp <= end) {
+			history = append(history, fill.OrderHistory)
+		}
+	}
+
+	last := (len(history) + limit - 1) / limit
+	if last == 0 {
+		last = 1
+	}
+	pagination := &model.OrderHistoryPagination{Page: page, Last: last}
+	if page > 1 {
+		pagination.Previous = page - 1
+	}
+	if page < last {
+		pagination.Next = page + 1
+	}
+	from := (page - 1) * limit
+	if from > len(history) {
+		from = len(history)
+	}
+	to := from + limit
+	if to > len(history) {
+		to = len(history)
+	}
+	return history[from:to], pagination, nil
+}
+
+// Match fills the open orders crossed by the books of their symbols. It stops at the first error.
+func (p *PaperTrader) Match(ctx context.Context) error {
+	p.mu.Lock()
+	symbols := map[Symbol]bool{}
+	for _, o := range p.open {
+		symbols[o.symbol] = true
+	}
+	p.mu.Unlock()
+
+	for symbol := range symbols {
+		book, err := p.client.GetMarketBooksCtx(ctx, symbol.Legacy(), p.depth)
+		if err != nil {
+			return fmt.Errorf("match %s: %w", symbol, err)
+		}
+		p.mu.Lock()
+		// resting buy orders are filled by the asks at or below their rate and the other way round
+		p.matchResting(symbol, OrderSideBuy, sortedLevels(book["asks"], false))
+		p.matchResting(symbol, OrderSideSell, sortedLevels(book["bids"], true))
+		p.mu.Unlock()
+	}
+	return nil
+}
+
+// AddTrade fills the open orders of symbol crossed by a trade of GetMarketTrades.
+func (p *PaperTrader) AddTrade(symbol string, trade model.MarketTrade) error {
+	s, err := ParseSymbol(symbol)
+	if err != nil {
+		return err
+	}
+	level := []model.MarketBidAndAsk{{Rate: trade.Rate, Amount: trade.Amount}}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// both sides share the amount of the trade
+	p.matchResting(s, OrderSideBuy, level)
+	p.matchResting(s, OrderSideSell, level)
+	return nil
+}
+
+// AddTradeEvent fills the open orders crossed by a trade of a trade stream, see MarketStream.SubscribeTrades.
+func (p *PaperTrader) AddTradeEvent(event model.TradeEvent) error {
+	return p.AddTrade(event.Symbol, model.MarketTrade{Timestamp: int(event.Timestamp), Rate: event.Rate, Amount: event.Amount})
+}
+
+// Run matches the open orders every match interval until ctx is done. Errors are reported to the
+// OnError handler and the next match is attempted as usual.
+func (p *PaperTrader) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := p.Match(ctx); err != nil && ctx.Err() == nil {
+			p.mu.Lock()
+			onError := p.onError
+			p.mu.Unlock()
+			if onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// matchResting fills the open orders of symbol and side, best rate first, with the amount of the
+// levels crossing them, at their own rate. The amounts taken are subtracted from levels.
+func (p *PaperTrader) matchResting(symbol Symbol, side string, levels []model.MarketBidAndAsk) {
+	for _, o := range p.sortedOpen(symbol) {
+		if o.side != side {
+			continue
+		}
+		for i := range levels {
+			if !o.remaining.IsPositive() {
+				break
+			}
+			if !o.crossed(levels[i].Rate) || !levels[i].Amount.IsPositive() {
+				continue
+			}
+			levels[i].Amount = levels[i].Amount.Sub(p.fill(o, o.rate, levels[i].Amount, true))
+		}
+	}
+}
+
+// fill fills o at rate with up to liquidity of the base asset, and returns the amount of the base
+// asset taken, nothing when rate is not positive. p.mu must be held.
+func (p *PaperTrader) fill(o *paperOrder, rate, liquidity decimal.Decimal, maker bool) decimal.Decimal {
+	if !rate.IsPositive() {
+		return decimal.Zero
+	}
+	feeRate := p.takerFee
+	if maker {
+		feeRate = p.makerFee
+	}
+	quote, base := p.balance(o.symbol.Quote), p.balance(o.symbol.Base)
+
+	var filled, taken, fee, received decimal.Decimal
+	if o.side == OrderSideBuy {
+		spend := decimal.Min(o.remaining, liquidity.Mul(rate))
+		if !spend.IsPositive() {
+			return decimal.Zero
+		}
+		fee = spend.Mul(feeRate)
+		filled = spend.Sub(fee).DivRound(rate, paperPrecision)
+		taken = spend.DivRound(rate, paperPrecision)
+		quote.Reserved = quote.Reserved.Sub(spend)
+		base.Available = base.Available.Add(filled)
+		o.remaining = o.remaining.Sub(spend)
+		received = filled
+	} else {
+		filled = decimal.Min(o.remaining, liquidity)
+		if !filled.IsPositive() {
+			return decimal.Zero
+		}
+		taken = filled
+		proceeds := filled.Mul(rate)
+		fee = proceeds.Mul(feeRate)
+		received = proceeds.Sub(fee)
+		base.Reserved = base.Reserved.Sub(filled)
+		quote.Available = quote.Available.Add(received)
+		o.remaining = o.remaining.Sub(filled)
+	}
+	o.fee = o.fee.Add(fee)
+	o.receive = o.receive.Add(received)
+	if !o.remaining.IsPositive() {
+		delete(p.open, o.id)
+	}
+
+	p.lastTxn++
+	p.fills = append(p.fills, paperFill{symbol: o.symbol, OrderHistory: model.OrderHistory{
+		TxnID:     fmt.Sprintf("%s%s%07d", o.symbol.Base, strings.ToUpper(o.side), p.lastTxn),
+		OrderID:   o.id,
+		Hash:      o.hash,
+		TakenByMe: !maker,
+		IsMaker:   maker,
+		Side:      o.side,
+		Type:      o.bitType,
+		Rate:      rate,
+		Fee:       fee,
+		Credit:    decimal.Zero,
+		Amount:    filled,
+		Receive:   received,
+		Timestamp: time.Now().Unix(),
+	}})
+	return taken
+}
+
+// release returns what is left of o to the available balance. p.mu must be held.
+func (p *PaperTrader) release(o *paperOrder) {
+	spent := p.balance(o.symbol.Base)
+	if o.side == OrderSideBuy {
+		spent = p.balance(o.symbol.Quote)
+	}
+	spent.Reserved = spent.Reserved.Sub(o.remaining)
+	spent.Available = spent.Available.Add(o.remaining)
+	o.remaining = decimal.Zero
+}
+
+// balance returns the balance of currency, creating it empty. p.mu must be held.
+func (p *PaperTrader) balance(currency string) *model.Balance {
+	balance, ok := p.balances[currency]
+	if !ok {
+		balance = &model.Balance{Available: decimal.Zero, Reserved: decimal.Zero}
+		p.balances[currency] = balance
+	}
+	return balance
+}
+
+// sortedOpen returns the open orders of symbol, buy orders first, then best rate first and oldest
+// first. p.mu must be held.
+func (p *PaperTrader) sortedOpen(symbol Symbol) []*paperOrder {
+	orders := []*paperOrder{}
+	for _, o := range p.open {
+		if o.symbol == symbol {
+			orders = append(orders, o)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		a, b := orders[i], orders[j]
+		if a.side != b.side {
+			return a.side == OrderSideBuy
+		}
+		if !a.rate.Equal(b.rate) {
+			return a.rate.GreaterThan(b.rate) == (a.side == OrderSideBuy)
+		}
+		return a.id < b.id
+	})
+	return orders
+}
+
+// crossed reports whether o would trade at rate.
+func (o *paperOrder) crossed(rate decimal.Decimal) bool {
+	if o.bitType == OrderTypeMarket {
+		return true
+	}
+	if o.side == OrderSideBuy {
+		return rate.LessThanOrEqual(o.rate)
+	}
+	return rate.GreaterThanOrEqual(o.rate)
+}
+
+// expected returns what is left to receive at the rate of o, before fees.
+func (o *paperOrder) expected() decimal.Decimal {
+	if o.rate.IsZero() {
+		return decimal.Zero
+	}
+	if o.side == OrderSideBuy {
+		return o.remaining.DivRound(o.rate, paperPrecision)
+	}
+	return o.remaining.Mul(o.rate)
+}
+
+func (o *paperOrder) order() *model.Order {
+	return &model.Order{
+		ID:        int64(o.id),
+		Hash:      o.hash,
+		Type:      o.bitType,
+		Amount:    o.amount,
+		Rate:      o.rate,
+		Fee:       o.fee,
+		Credit:    decimal.Zero,
+		Receive:   o.receive.Add(o.expected()),
+		Timestamp: o.ts,
+		ClientID:  o.clientID,
+	}
+}
+
+// sortedLevels returns the levels best first: highest rate first for bids, lowest first for asks.
+func sortedLevels(levels []model.MarketBidAndAsk, bids bool) []model.MarketBidAndAsk {
+	sorted := append([]model.MarketBidAndAsk{}, levels...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if bids {
+			return sorted[i].Rate.GreaterThan(sorted[j].Rate)
+		}
+		return sorted[i].Rate.LessThan(sorted[j].Rate)
+	})
+	return sorted
+}
+
+// paperError is the error Bitkub answers with code.
+func paperError(code int, endpoint string) *APIError {
+	return &APIError{Code: code, Message: internal.GetErrorMessage(code), Endpoint: endpoint}
+}
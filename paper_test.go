@@ -0,0 +1,197 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+func balancesString(balances map[string]model.Balance) string {
+	return fmt.Sprintf("THB %s/%s BTC %s/%s", balances["THB"].Available, balances["THB"].Reserved, balances["BTC"].Available, balances["BTC"].Reserved)
+}
+
+func TestPaperTrader(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		book string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/market/books" || r.URL.Query().Get("sym") != "THB_BTC" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, `{"error":0,"result":%s}`, book)
+	}))
+	defer srv.Close()
+	setBook := func(b string) {
+		mu.Lock()
+		book = b
+		mu.Unlock()
+	}
+
+	api := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil))
+	d := decimal.RequireFromString
+	paper := api.NewPaperTrader(map[string]decimal.Decimal{"THB": d("10000"), "btc": d("0.01")},
+		bitkub.WithPaperFees(d("0.001"), d("0.002")))
+	var trader bitkub.Trader = paper
+
+	// the bid takes the two asks at or below its rate, and rests with the other 1000 THB
+	setBook(`{"bids":[[1,0,990,990000,0.001]],"asks":[[2,0,995,995000,0.001],[3,0,1000,1000000,0.001],[4,0,1010,1010000,0.01]]}`)
+	order, err := trader.PlaceBid("btc_thb", bitkub.OrderTypeLimit, d("3000"), d("1000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.ID != 1 || order.Fee.String() != "3.99" || order.Receive.String() != "0.003001" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+	balances, _ := trader.GetBalances()
+	if s := balancesString(balances); s != "THB 7000/1005 BTC 0.011996/0" {
+		t.Fatalf("unexpected balances %s", s)
+	}
+	open, _ := trader.GetOpenOrder("THB_BTC")
+	if len(open) != 1 || open[0].Amount.String() != "1005" || open[0].Side != bitkub.OrderSideBuy {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+
+	// a trade at the rate fills part of the resting bid as a maker, then the book crossing it the rest
+	if err := paper.AddTrade("THB_BTC", model.MarketTrade{Rate: d("1000000"), Amount: d("0.0005")}); err != nil {
+		t.Fatal(err)
+	}
+	if open, _ := trader.GetOpenOrder("THB_BTC"); len(open) != 1 || open[0].Amount.String() != "505" {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+	setBook(`{"bids":[[1,0,990,990000,0.001]],"asks":[[2,0,9990,999000,0.01]]}`)
+	if err := paper.Match(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if open, _ := trader.GetOpenOrder("THB_BTC"); len(open) != 0 {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+
+	history, pagination, err := trader.GetOrderHistory("THB_BTC", 1, 2, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || pagination.Last != 2 || pagination.Next != 2 {
+		t.Fatalf("unexpected history %+v %+v", history, pagination)
+	}
+	if h := history[0]; !h.IsMaker || h.Rate.String() != "1000000" || h.Amount.String() != "0.0005045" || h.Fee.String() != "0.505" || h.Receive.String() != "0.0005045" {
+		t.Fatalf("unexpected fill %+v", h)
+	}
+	if h := history[1]; !h.IsMaker || h.Amount.String() != "0.0004995" {
+		t.Fatalf("unexpected fill %+v", h)
+	}
+	if older, _, _ := trader.GetOrderHistory("THB_BTC", 2, 2, 0, 0); len(older) != 2 || older[1].IsMaker || older[1].Rate.String() != "995000" {
+		t.Fatalf("unexpected fills %+v", older)
+	}
+
+	// a market sell takes the only bid and gives up the rest
+	order, err = trader.PlaceAsk("THB_BTC", bitkub.OrderTypeMarket, d("0.002"), decimal.Zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Receive.String() != "988.02" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+	if latest, _, _ := trader.GetOrderHistory("THB_BTC", 1, 1, 0, 0); len(latest) != 1 || latest[0].Receive.String() != "988.02" {
+		t.Fatalf("unexpected fills %+v", latest)
+	}
+	balances, _ = trader.GetBalances()
+	if s := balancesString(balances); s != "THB 7988.02/0 BTC 0.012/0" {
+		t.Fatalf("unexpected balances %s", s)
+	}
+
+	// a resting ask is cancelled
+	order, err = trader.PlaceAsk("THB_BTC", bitkub.OrderTypeLimit, d("0.005"), d("2000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trader.CancelOrder("THB_BTC", bitkub.OrderSideSell, order.Hash, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := trader.CancelOrder("THB_BTC", bitkub.OrderSideSell, order.Hash, 0); !errors.Is(err, &bitkub.APIError{Code: 21}) {
+		t.Fatalf("expected error 21, got %v", err)
+	}
+	// by id, the symbol must match like with Bitkub
+	order, err = trader.PlaceAsk("THB_BTC", bitkub.OrderTypeLimit, d("0.005"), d("2000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trader.CancelOrder("THB_ETH", bitkub.OrderSideSell, "", int(order.ID)); !errors.Is(err, &bitkub.APIError{Code: 21}) {
+		t.Fatalf("expected error 21, got %v", err)
+	}
+	if err := trader.CancelOrder("btc_thb", bitkub.OrderSideSell, "", int(order.ID)); err != nil {
+		t.Fatal(err)
+	}
+	balances, _ = trader.GetBalances()
+	if s := balancesString(balances); s != "THB 7988.02/0 BTC 0.012/0" {
+		t.Fatalf("unexpected balances %s", s)
+	}
+
+	if _, err := trader.PlaceBid("THB_BTC", bitkub.OrderTypeMarket, d("8000"), decimal.Zero); !errors.Is(err, bitkub.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+	if _, err := trader.GetOpenOrder(""); err == nil || !strings.Contains(err.Error(), "symbol is empty") {
+		t.Fatalf("expected an empty symbol error, got %v", err)
+	}
+}
+
+func TestPaperTraderTradeLiquidity(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		book = `{"bids":[],"asks":[]}`
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, `{"error":0,"result":%s}`, book)
+	}))
+	defer srv.Close()
+
+	d := decimal.RequireFromString
+	paper := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil)).
+		NewPaperTrader(map[string]decimal.Decimal{"THB": d("1000"), "BTC": d("0.001")}, bitkub.WithPaperFees(decimal.Zero, decimal.Zero))
+	if _, err := paper.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, d("1000"), d("1000000")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := paper.PlaceAsk("THB_BTC", bitkub.OrderTypeLimit, d("0.001"), d("900000")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the trade crosses both orders but its amount is only enough for the bid
+	if err := paper.AddTrade("THB_BTC", model.MarketTrade{Rate: d("950000"), Amount: d("0.001")}); err != nil {
+		t.Fatal(err)
+	}
+	open, _ := paper.GetOpenOrder("THB_BTC")
+	if len(open) != 1 || open[0].Side != bitkub.OrderSideSell || open[0].Amount.String() != "0.001" {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+
+	// a level without a rate is not taken
+	mu.Lock()
+	book = `{"bids":[[1,0,0,0,0.001]],"asks":[]}`
+	mu.Unlock()
+	if err := paper.CancelOrder("THB_BTC", bitkub.OrderSideSell, open[0].Hash, 0); err != nil {
+		t.Fatal(err)
+	}
+	order, err := paper.PlaceAsk("THB_BTC", bitkub.OrderTypeMarket, d("0.001"), decimal.Zero)
+	if err != nil || !order.Receive.IsZero() {
+		t.Fatalf("unexpected order %+v %v", order, err)
+	}
+	if balances, _ := paper.GetBalances(); balancesString(balances) != "THB 0/0 BTC 0.002/0" {
+		t.Fatalf("unexpected balances %s", balancesString(balances))
+	}
+	if _, err := paper.PlaceOrder(bitkub.SellOrder("THB_BTC").Market().QuoteAmount(d("100"))); !errors.Is(err, &bitkub.APIError{Code: 14}) {
+		t.Fatalf("expected error 14, got %v", err)
+	}
+}
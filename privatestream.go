@@ -0,0 +1,210 @@
+package bitkub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+const (
+	privateStreamPath  = "/private"
+	orderUpdateChannel = "order_update"
+	matchUpdateChannel = "match_update"
+)
+
+// PrivateStream delivers the order updates and fills of the account over a WebSocket connection
+// authenticated with the token of GetWebSocketToken. Handlers are called one message at a time
+// from the goroutine running Run, so they should not block.
+//
+// A new token is fetched on each connection and every token refresh interval. A rejected token stops
+// the stream with a *StreamAuthError, as the credentials would be rejected again. Dropped connections
+// are detected and opened again like those of MarketStream, but updates sent in between are lost,
+// so check GetOpenOrder after a reconnect when an order may have changed meanwhile.
+type PrivateStream struct {
+	client *Client
+	runner *streamRunner
+
+	mu      sync.Mutex
+	orders  []func(model.OrderUpdateEvent)
+	fills   []func(model.FillEvent)
+	onError func(error)
+}
+
+// NewPrivateStream creates a private stream using the credentials, WebSocket URL and user agent of the client.
+func (b *Client) NewPrivateStream(opts ...StreamOption) *PrivateStream {
+	s := &PrivateStream{client: b}
+	s.runner = &streamRunner{
+		config:    newStreamConfig(opts),
+		dial:      s.dial,
+		connected: s.authenticate,
+		handle:    s.dispatch,
+	}
+	return s
+}
+
+// OnOrderUpdate calls handler when an order is created, filled, partially filled or cancelled.
+func (s *PrivateStream) OnOrderUpdate(handler func(model.OrderUpdateEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders = append(s.orders, handler)
+}
+
+// OnFill calls handler for each fill of an order.
+func (s *PrivateStream) OnFill(handler func(model.FillEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fills = append(s.fills, handler)
+}
+
+// OnError sets the handler of messages which cannot be decoded and of failed token refreshes.
+func (s *PrivateStream) OnError(handler func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = handler
+}
+
+// OnStateChange sets the handler of connection state changes. StreamConnected is reported once authenticated and subscribed.
+func (s *PrivateStream) OnStateChange(handler func(StreamStateEvent)) {
+	s.runner.setOnState(handler)
+}
+
+// Run connects and delivers messages until ctx is done, a token is rejected or the reconnect policy gives up.
+func (s *PrivateStream) Run(ctx context.Context) error {
+	return s.runner.run(ctx)
+}
+
+func (s *PrivateStream) dial(ctx context.Context) (*internal.WSConn, error) {
+	return s.client.dialWebSocket(ctx, privateStreamPath)
+}
+
+// authenticate sends a fresh token, waits for the answer and subscribes to the channels. The token
+// is then refreshed in the background until the connection ends.
+func (s *PrivateStream) authenticate(ctx context.Context, conn *internal.WSConn, reconnected bool) error {
+	if err := s.sendToken(ctx, conn); err != nil {
+		return err
+	}
+
+	timeout := s.client.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		auth := model.StreamAuthEvent{}
+		if err := json.Unmarshal(data, &auth); err != nil || auth.Event != "auth" {
+			continue
+		}
+		if auth.Code != "200" {
+			return &StreamAuthError{Code: auth.Code, Message: auth.Message}
+		}
+		break
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	for _, channel := range []string{orderUpdateChannel, matchUpdateChannel} {
+		if err := s.send(conn, map[string]interface{}{"event": "subscribe", "channel": channel}); err != nil {
+			return err
+		}
+	}
+
+	if interval := s.runner.config.tokenRefresh; interval > 0 {
+		go s.refresh(ctx, conn, interval)
+	}
+	return nil
+}
+
+// refresh authenticates again with a new token every interval until ctx is done.
+func (s *PrivateStream) refresh(ctx context.Context, conn *internal.WSConn, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sendToken(ctx, conn); err != nil && ctx.Err() == nil {
+				s.error(fmt.Errorf("refresh stream token: %w", err))
+			}
+		}
+	}
+}
+
+func (s *PrivateStream) sendToken(ctx context.Context, conn *internal.WSConn) error {
+	token, err := s.client.GetWebSocketTokenCtx(ctx)
+	if err != nil {
+		return err
+	}
+	return s.send(conn, map[string]interface{}{"event": "auth", "data": map[string]string{"token": token}})
+}
+
+func (s *PrivateStream) send(conn *internal.WSConn, message map[string]interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(internal.OpText, data)
+}
+
+// dispatch decodes a message and calls the handlers of its channel.
+func (s *PrivateStream) dispatch(data []byte) {
+	envelope := struct {
+		Event   string          `json:"event"`
+		Code    string          `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		s.error(fmt.Errorf("decode stream message: %w", err))
+		return
+	}
+
+	switch envelope.Event {
+	case "auth":
+		// answer of a token refresh, a rejection stops the stream
+		if envelope.Code != "200" {
+			s.runner.drop(&StreamAuthError{Code: envelope.Code, Message: envelope.Message})
+		}
+	case orderUpdateChannel:
+		event := model.OrderUpdateEvent{}
+		if err := json.Unmarshal(envelope.Data, &event); err != nil {
+			s.error(fmt.Errorf("decode %s: %w", envelope.Event, err))
+			return
+		}
+		s.mu.Lock()
+		handlers := s.orders
+		s.mu.Unlock()
+		for _, handler := range handlers {
+			handler(event)
+		}
+	case matchUpdateChannel:
+		event := model.FillEvent{}
+		if err := json.Unmarshal(envelope.Data, &event); err != nil {
+			s.error(fmt.Errorf("decode %s: %w", envelope.Event, err))
+			return
+		}
+		s.mu.Lock()
+		handlers := s.fills
+		s.mu.Unlock()
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+}
+
+func (s *PrivateStream) error(err error) {
+	s.mu.Lock()
+	handler := s.onError
+	s.mu.Unlock()
+	if handler != nil {
+		handler(err)
+	}
+}
@@ -0,0 +1,190 @@
+package bitkub_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// privateStreamServer serves tokens numbered from 1 and passes each private connection to handler.
+func privateStreamServer(t *testing.T, handler func(conn *internal.WSConn)) *bitkub.Client {
+	api, mux := streamServer(t, func(path string, conn *internal.WSConn) {
+		if path != "/websocket-api/private" {
+			t.Errorf("unexpected path %s", path)
+			return
+		}
+		handler(conn)
+	})
+	var tokens int32
+	mux.HandleFunc("/api/market/wstoken", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"error":0,"result":"token-%d"}`, atomic.AddInt32(&tokens, 1))
+	})
+	api.ApiKey, api.ApiSecret = "key", "secret"
+	return api
+}
+
+// readEvent reads the next message sent by the client.
+func readEvent(conn *internal.WSConn) (map[string]interface{}, error) {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	message := map[string]interface{}{}
+	return message, json.Unmarshal(data, &message)
+}
+
+// acceptAuth answers the auth message and returns its token.
+func acceptAuth(t *testing.T, conn *internal.WSConn) string {
+	message, err := readEvent(conn)
+	if err != nil || message["event"] != "auth" {
+		t.Errorf("expected auth, got %v, %v", message, err)
+		return ""
+	}
+	conn.WriteMessage(internal.OpText, []byte(`{"event":"auth","code":"200","message":"success"}`))
+	return message["data"].(map[string]interface{})["token"].(string)
+}
+
+func TestPrivateStream(t *testing.T) {
+	var channels []string
+	api := privateStreamServer(t, func(conn *internal.WSConn) {
+		if token := acceptAuth(t, conn); token != "token-1" {
+			t.Errorf("unexpected token %s", token)
+		}
+		for i := 0; i < 2; i++ {
+			message, _ := readEvent(conn)
+			channels = append(channels, fmt.Sprint(message["event"], " ", message["channel"]))
+		}
+		conn.WriteMessage(internal.OpText, []byte(`{"event":"order_update","data":{"order_id":"1001","client_id":"my-1","symbol":"BTC_THB","side":"buy","type":"limit","status":"partial_filled","rate":"2000000.5","amount":"0.01","filled_amount":"0.004","remaining_amount":"0.006","ts":1707220636000}}`))
+		conn.WriteMessage(internal.OpText, []byte(`{"event":"match_update","data":"oops"}`))
+		conn.WriteMessage(internal.OpText, []byte(`{"event":"match_update","data":{"txn_id":"BTCBUY0001","order_id":"1001","symbol":"BTC_THB","side":"buy","rate":2000000.5,"amount":0.004,"fee":"20","is_maker":true,"ts":1707220636000}}`))
+		conn.ReadMessage()
+	})
+
+	stream := api.NewPrivateStream()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		orders []model.OrderUpdateEvent
+		fills  []model.FillEvent
+		errs   []error
+	)
+	stream.OnOrderUpdate(func(e model.OrderUpdateEvent) { orders = append(orders, e) })
+	stream.OnFill(func(e model.FillEvent) {
+		fills = append(fills, e)
+		cancel()
+	})
+	stream.OnError(func(err error) { errs = append(errs, err) })
+
+	if err := stream.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if strings.Join(channels, ",") != "subscribe order_update,subscribe match_update" {
+		t.Fatalf("unexpected subscriptions %v", channels)
+	}
+	if len(orders) != 1 || orders[0].OrderID != "1001" || orders[0].ClientID != "my-1" || orders[0].Status != "partial_filled" || orders[0].FilledAmount.String() != "0.004" {
+		t.Fatalf("unexpected order updates %+v", orders)
+	}
+	if len(fills) != 1 || fills[0].TxnID != "BTCBUY0001" || fills[0].Rate.String() != "2000000.5" || fills[0].Fee.String() != "20" || !fills[0].IsMaker {
+		t.Fatalf("unexpected fills %+v", fills)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "match_update") {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
+
+func TestPrivateStreamTokenRefresh(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		tokens []string
+	)
+	api := privateStreamServer(t, func(conn *internal.WSConn) {
+		for {
+			message, err := readEvent(conn)
+			if err != nil {
+				return
+			}
+			if message["event"] != "auth" {
+				continue
+			}
+			mu.Lock()
+			tokens = append(tokens, message["data"].(map[string]interface{})["token"].(string))
+			n := len(tokens)
+			mu.Unlock()
+			conn.WriteMessage(internal.OpText, []byte(`{"event":"auth","code":"200"}`))
+			if n == 3 {
+				conn.WriteMessage(internal.OpText, []byte(`{"event":"order_update","data":{"order_id":"1","status":"new"}}`))
+			}
+		}
+	})
+
+	stream := api.NewPrivateStream(fastReconnect(5), bitkub.WithTokenRefresh(50*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var connects int32
+	stream.OnStateChange(func(e bitkub.StreamStateEvent) {
+		if e.State == bitkub.StreamConnected {
+			atomic.AddInt32(&connects, 1)
+		}
+	})
+	stream.OnOrderUpdate(func(e model.OrderUpdateEvent) { cancel() })
+
+	if err := stream.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if n := atomic.LoadInt32(&connects); n != 1 {
+		t.Fatalf("expected a single connection, got %d", n)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(tokens[:3], ",") != "token-1,token-2,token-3" {
+		t.Fatalf("unexpected tokens %v", tokens)
+	}
+}
+
+func TestPrivateStreamAuthFailure(t *testing.T) {
+	for name, refresh := range map[string]bool{"connect": false, "refresh": true} {
+		t.Run(name, func(t *testing.T) {
+			var connections int32
+			api := privateStreamServer(t, func(conn *internal.WSConn) {
+				atomic.AddInt32(&connections, 1)
+				readEvent(conn)
+				if refresh {
+					conn.WriteMessage(internal.OpText, []byte(`{"event":"auth","code":"200"}`))
+					for {
+						message, err := readEvent(conn)
+						if err != nil || message["event"] == "auth" {
+							break
+						}
+					}
+				}
+				conn.WriteMessage(internal.OpText, []byte(`{"event":"auth","code":"401","message":"invalid token"}`))
+				conn.ReadMessage()
+			})
+
+			// the rejection is not retried whatever the reconnect policy
+			stream := api.NewPrivateStream(fastReconnect(5), bitkub.WithTokenRefresh(50*time.Millisecond))
+			var authErr *bitkub.StreamAuthError
+			if err := stream.Run(context.Background()); !errors.As(err, &authErr) || authErr.Message != "invalid token" {
+				t.Fatalf("expected an auth error, got %v", err)
+			}
+			if n := atomic.LoadInt32(&connections); n != 1 {
+				t.Fatalf("expected 1 connection, got %d", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package bitkub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitGroup is a group of endpoints sharing a request quota.
+type RateLimitGroup string
+
+const (
+	RateLimitMarket RateLimitGroup = "market" // public market data
+	RateLimitDepth  RateLimitGroup = "depth"  // /api/market/depth
+	RateLimitTrade  RateLimitGroup = "trade"  // orders and balances
+	RateLimitWallet RateLimitGroup = "wallet" // crypto, fiat and user endpoints
+)
+
+// RateLimit is a token bucket quota: Rate requests per second with bursts of up to Burst requests.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultRateLimits are the quotas enforced by Bitkub for each group of endpoints.
+var DefaultRateLimits = map[RateLimitGroup]RateLimit{
+	RateLimitMarket: {Rate: 100, Burst: 100},
+	RateLimitDepth:  {Rate: 10, Burst: 10},
+	RateLimitTrade:  {Rate: 150, Burst: 150},
+	RateLimitWallet: {Rate: 100, Burst: 100},
+}
+
+// ErrRateLimited is returned by a fail-fast RateLimiter when the quota of the endpoint is used up.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// tradeEndpoints are the /api/market endpoints counted in RateLimitTrade.
+var tradeEndpoints = map[string]bool{
+	"/api/market/wallet":            true,
+	"/api/market/balances":          true,
+	"/api/market/place-bid":         true,
+	"/api/market/place-bid/test":    true,
+	"/api/market/place-ask":         true,
+	"/api/market/place-ask/test":    true,
+	"/api/market/place-ask-by-fiat": true,
+	"/api/market/cancel-order":      true,
+	"/api/market/my-open-orders":    true,
+	"/api/market/my-order-history":  true,
+	"/api/market/order-info":        true,
+	"/api/market/wstoken":           true,
+}
+
+func rateLimitGroupOf(endpoint string) RateLimitGroup {
+	switch {
+	case endpoint == "/api/market/depth":
+		return RateLimitDepth
+	case tradeEndpoints[endpoint]:
+		return RateLimitTrade
+	case strings.HasPrefix(endpoint, "/api/crypto/"),
+		strings.HasPrefix(endpoint, "/api/fiat/"),
+		strings.HasPrefix(endpoint, "/api/user/"):
+		return RateLimitWallet
+	}
+	return RateLimitMarket
+}
+
+// RateLimiter throttles requests per group of endpoints. It is safe for concurrent use.
+type RateLimiter struct {
+	failFast bool
+	limits   map[RateLimitGroup]RateLimit
+
+	mu      sync.Mutex
+	buckets map[RateLimitGroup]*tokenBucket
+}
+
+// NewRateLimiter creates a RateLimiter with the given quotas. Groups missing from limits are not throttled.
+// When failFast is true requests over quota fail with ErrRateLimited, otherwise they wait for a token.
+func NewRateLimiter(limits map[RateLimitGroup]RateLimit, failFast bool) *RateLimiter {
+	l := &RateLimiter{
+		failFast: failFast,
+		limits:   map[RateLimitGroup]RateLimit{},
+		buckets:  map[RateLimitGroup]*tokenBucket{},
+	}
+	for group, limit := range limits {
+		l.limits[group] = limit
+	}
+	return l
+}
+
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = map[string]*RateLimiter{}
+)
+
+// sharedRateLimiter returns the blocking RateLimiter with DefaultRateLimits shared by all clients using key.
+func sharedRateLimiter(key string) *RateLimiter {
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+
+	l, ok := sharedLimiters[key]
+	if !ok {
+		l = NewRateLimiter(DefaultRateLimits, false)
+		sharedLimiters[key] = l
+	}
+	return l
+}
+
+func (l *RateLimiter) bucket(group RateLimitGroup) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tb, ok := l.buckets[group]
+	if !ok {
+		limit, ok := l.limits[group]
+		if !ok || limit.Rate <= 0 {
+			return nil
+		}
+		burst := float64(limit.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+		tb = &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+		l.buckets[group] = tb
+	}
+	return tb
+}
+
+// Wait takes a token for the given group, waiting for one unless the limiter fails fast.
+func (l *RateLimiter) Wait(ctx context.Context, group RateLimitGroup) error {
+	tb := l.bucket(group)
+	if tb == nil {
+		return nil
+	}
+
+	wait, ok := tb.reserve(time.Now(), !l.failFast)
+	if !ok {
+		return ErrRateLimited
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		tb.release()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Pause stops handing out tokens for the group until the given time, e.g. after Bitkub answered with HTTP 429.
+func (l *RateLimiter) Pause(group RateLimitGroup, until time.Time) {
+	if tb := l.bucket(group); tb != nil {
+		tb.pause(until)
+	}
+}
+
+type tokenBucket struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// reserve takes a token and returns how long to wait before it may be used.
+// When block is false and the token is not available right away, nothing is taken.
+func (tb *tokenBucket) reserve(now time.Time, block bool) (time.Duration, bool) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+
+	var wait time.Duration
+	if tb.tokens < 1 {
+		wait = time.Duration((1 - tb.tokens) / tb.rate * float64(time.Second))
+	}
+	if paused := tb.pausedUntil.Sub(now); paused > wait {
+		wait = paused
+	}
+	if wait > 0 && !block {
+		return 0, false
+	}
+
+	tb.tokens--
+	return wait, true
+}
+
+func (tb *tokenBucket) release() {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.tokens++
+}
+
+func (tb *tokenBucket) pause(until time.Time) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if until.After(tb.pausedUntil) {
+		tb.pausedUntil = until
+	}
+}
@@ -0,0 +1,77 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+)
+
+func TestRateLimiterFailFast(t *testing.T) {
+	l := bitkub.NewRateLimiter(map[bitkub.RateLimitGroup]bitkub.RateLimit{bitkub.RateLimitMarket: {Rate: 1, Burst: 2}}, true)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx, bitkub.RateLimitMarket); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if err := l.Wait(ctx, bitkub.RateLimitMarket); !errors.Is(err, bitkub.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if err := l.Wait(ctx, bitkub.RateLimitTrade); err != nil {
+		t.Fatalf("groups without a limit must not be throttled: %v", err)
+	}
+}
+
+func TestRateLimiterBlocking(t *testing.T) {
+	l := bitkub.NewRateLimiter(map[bitkub.RateLimitGroup]bitkub.RateLimit{bitkub.RateLimitMarket: {Rate: 20, Burst: 1}}, false)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background(), bitkub.RateLimitMarket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected requests to be spread over 100ms, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx, bitkub.RateLimitMarket); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"error":0,"result":[]}`))
+	}))
+	defer srv.Close()
+
+	api := bitkub.NewClient("", "",
+		bitkub.WithBaseURL(srv.URL),
+		bitkub.WithRateLimiter(bitkub.NewRateLimiter(bitkub.DefaultRateLimits, false)),
+		bitkub.WithRetryPolicy(&bitkub.BackoffPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+	)
+
+	start := time.Now()
+	if _, err := api.GetMarketSymbols(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || calls != 2 {
+		t.Fatalf("expected a retry after 1s, got %d calls in %s", calls, elapsed)
+	}
+}
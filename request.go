@@ -0,0 +1,275 @@
+package bitkub
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// mutatingEndpoints are the endpoints which must not be sent twice once they may have been
+// executed. Bitkub accepts a repeated client id, so a client id does not make them safe to resend.
+var mutatingEndpoints = map[string]bool{
+	"/api/market/place-bid":         true,
+	"/api/market/place-ask":         true,
+	"/api/market/place-ask-by-fiat": true,
+	"/api/market/cancel-order":      true,
+	"/api/crypto/withdraw":          true,
+	"/api/crypto/internal-withdraw": true,
+	"/api/crypto/generate-address":  true,
+	"/api/fiat/withdraw":            true,
+}
+
+// endpointOf returns the path without its query, with v3 paths mapped to their legacy
+// counterpart so both share the same rate limit and retry rules.
+func endpointOf(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return strings.Replace(path, "/api/v3/", "/api/", 1)
+}
+
+// signer returns the headers and body of a signed request.
+type signer func(method, path string, payload map[string]interface{}) (map[string]string, []byte, error)
+
+// get sends a request to a public endpoint and decodes the response into out.
+func (b *Client) get(ctx context.Context, path string, out interface{}) error {
+	return b.withRetry(ctx, endpointOf(path), true, func() error {
+		return b.send(ctx, "GET", path, nil, nil, out)
+	})
+}
+
+// post signs the payload with the legacy scheme, sends it to a secure endpoint and decodes the response into out.
+func (b *Client) post(ctx context.Context, path string, payload map[string]interface{}, out interface{}) error {
+	return b.secure(ctx, "POST", path, payload, out, b.signLegacy)
+}
+
+// secure sends a request to a secure endpoint and decodes the response into out.
+// The request is signed again for each retry.
+func (b *Client) secure(ctx context.Context, method, path string, payload map[string]interface{}, out interface{}, sign signer) error {
+	if b.ApiKey == "" {
+		return &internal.NotSentError{Err: fmt.Errorf("api key is empty")}
+	}
+	if b.ApiSecret == "" {
+		return &internal.NotSentError{Err: fmt.Errorf("api secret is empty")}
+	}
+
+	endpoint := endpointOf(path)
+	if b.dryRun && mutatingEndpoints[endpoint] {
+		var simulated bool
+		if path, simulated = dryRunPath(path, endpoint); simulated {
+			b.logDryRun(DryRunRequest{Method: method, Path: path, Payload: payload, Simulated: true})
+			return nil
+		}
+		b.logDryRun(DryRunRequest{Method: method, Path: path, Payload: payload})
+		endpoint = endpointOf(path)
+	}
+	idempotent := !mutatingEndpoints[endpoint]
+
+	b.maybeSyncClock(ctx)
+
+	return b.withRetry(ctx, endpoint, idempotent, func() error {
+		headers, body, err := sign(method, path, payload)
+		if err != nil {
+			return &internal.NotSentError{Err: err}
+		}
+
+		err = b.send(ctx, method, path, headers, body, out)
+		b.resyncOnInvalidTimestamp(ctx, err)
+		return err
+	})
+}
+
+// signLegacy adds ts and sig to the JSON payload.
+func (b *Client) signLegacy(method, path string, payload map[string]interface{}) (map[string]string, []byte, error) {
+	body, err := b.sigPayload(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	headers := map[string]string{"X-BTK-APIKEY": b.ApiKey, "Content-Type": "application/json", "Accept": "application/json"}
+	return headers, body, nil
+}
+
+// signV3 signs timestamp + method + path with query + body and sends the signature in the X-BTK-SIGN header.
+func (b *Client) signV3(method, path string, payload map[string]interface{}) (map[string]string, []byte, error) {
+	var body []byte
+	if method != "GET" {
+		if payload == nil {
+			payload = map[string]interface{}{}
+		}
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ts := strconv.FormatInt(b.now().UnixMilli(), 10)
+	h := hmac.New(sha256.New, []byte(b.ApiSecret))
+	h.Write([]byte(ts + method + path))
+	h.Write(body)
+
+	headers := map[string]string{
+		"X-BTK-APIKEY":    b.ApiKey,
+		"X-BTK-TIMESTAMP": ts,
+		"X-BTK-SIGN":      hex.EncodeToString(h.Sum(nil)),
+		"Content-Type":    "application/json",
+		"Accept":          "application/json",
+	}
+	return headers, body, nil
+}
+
+// send waits for the rate limiter, sends a single request and decodes the response into out.
+func (b *Client) send(ctx context.Context, method, path string, headers map[string]string, payload []byte, out interface{}) error {
+	group := rateLimitGroupOf(endpointOf(path))
+	if b.limiter != nil {
+		if err := b.limiter.Wait(ctx, group); err != nil {
+			return &internal.NotSentError{Err: err}
+		}
+	}
+
+	resp, err := b.do(ctx, method, path, headers, payload)
+	if err != nil {
+		return err
+	}
+
+	err = decodeResponse(path, resp, out)
+	var apiErr *APIError
+	if b.limiter != nil && errors.As(err, &apiErr) && apiErr.HTTPStatus == http.StatusTooManyRequests {
+		b.limiter.Pause(group, time.Now().Add(apiErr.RetryAfter))
+	}
+	return err
+}
+
+// do sends a request to path relative to the base URL. The zero Client falls back to the defaults of NewClient.
+func (b *Client) do(ctx context.Context, method, path string, headers map[string]string, payload []byte) (*internal.Response, error) {
+	baseURL, userAgent, transport := b.baseURL, b.userAgent, b.transport
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	if transport == nil {
+		transport = &internal.FastHTTPTransport{}
+	}
+
+	timeout := b.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	reqHeaders := map[string]string{"User-Agent": userAgent}
+	for k, v := range b.headers {
+		reqHeaders[k] = v
+	}
+	for k, v := range headers {
+		reqHeaders[k] = v
+	}
+
+	return transport.Do(ctx, &internal.Request{
+		Method:  method,
+		URL:     baseURL + path,
+		Headers: reqHeaders,
+		Body:    payload,
+	})
+}
+
+// sigPayload signs a copy of payload with a new ts, so that the payload can be signed again for a
+// retry without the sig of the previous attempt.
+func (b *Client) sigPayload(payload map[string]interface{}) ([]byte, error) {
+	signed := make(map[string]interface{}, len(payload)+2)
+	for k, v := range payload {
+		if k != "sig" {
+			signed[k] = v
+		}
+	}
+	signed["ts"] = b.now().UnixMilli()
+	body, err := json.Marshal(signed)
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(sha256.New, []byte(b.ApiSecret))
+	if _, err := h.Write(body); err != nil {
+		return nil, err
+	}
+	signed["sig"] = hex.EncodeToString(h.Sum(nil))
+	body, err = json.Marshal(signed)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// decodeResponse turns an HTTP error status or a non-zero Bitkub error code into an *APIError,
+// otherwise it decodes the body into out. A nil out only checks for errors.
+func decodeResponse(path string, resp *internal.Response, out interface{}) error {
+	endpoint := path
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		endpoint = path[:i]
+	}
+
+	body := bytes.TrimSpace(resp.Body())
+	code := 0
+	if len(body) > 0 && body[0] == '{' {
+		ret := model.ErrorResponse{}
+		if err := json.Unmarshal(body, &ret); err == nil {
+			code = ret.Error
+		}
+	}
+
+	if code != 0 {
+		return &APIError{
+			Code:       code,
+			HTTPStatus: resp.StatusCode(),
+			Message:    internal.GetErrorMessage(code),
+			Endpoint:   endpoint,
+			Body:       resp.String(),
+		}
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return &APIError{
+			HTTPStatus: resp.StatusCode(),
+			Message:    http.StatusText(resp.StatusCode()),
+			Endpoint:   endpoint,
+			Body:       resp.String(),
+			RetryAfter: parseRetryAfter(resp.Header("Retry-After")),
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return &DecodeError{Endpoint: endpoint, Body: resp.String(), Err: err}
+	}
+	return nil
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,170 @@
+package bitkub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/internal"
+)
+
+// RetryAttempt describes a failed request for a RetryPolicy.
+type RetryAttempt struct {
+	Endpoint   string // path of the endpoint, e.g. /api/market/place-bid
+	Idempotent bool   // true when sending the request again cannot have side effects
+	Attempt    int    // number of attempts made so far, starting at 1
+	Err        error  // error of the last attempt
+}
+
+// RetryPolicy decides whether a failed request is sent again and how long to wait before doing so.
+type RetryPolicy interface {
+	Retry(a RetryAttempt) (time.Duration, bool)
+}
+
+// BackoffPolicy retries with exponential backoff and full jitter.
+//
+// Requests that may have reached Bitkub, after a network error, an HTTP 5xx or the error 90, are
+// only retried when they are idempotent. Placing orders, cancels and withdrawals are not, even
+// with a client order id. Requests rejected because of their timestamp or by the rate limit are
+// always retried as they were not executed.
+type BackoffPolicy struct {
+	MaxAttempts int           // attempts including the first one, values below 2 disable retries
+	BaseDelay   time.Duration // delay before the first retry, doubled for each further retry
+	MaxDelay    time.Duration // upper bound of the delay
+
+	// Endpoints overrides the policy for the given endpoint paths.
+	Endpoints map[string]*BackoffPolicy
+}
+
+// DefaultRetryPolicy returns the policy used by NewClient.
+func DefaultRetryPolicy() *BackoffPolicy {
+	return &BackoffPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+	}
+}
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func (p *BackoffPolicy) Retry(a RetryAttempt) (time.Duration, bool) {
+	if override, ok := p.Endpoints[a.Endpoint]; ok {
+		return override.Retry(a)
+	}
+	if a.Attempt >= p.MaxAttempts || !retryable(a.Err, a.Idempotent) {
+		return 0, false
+	}
+
+	delay := p.BaseDelay << uint(a.Attempt-1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0, true
+	}
+
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return time.Duration(jitterRand.Int63n(int64(delay) + 1)), true
+}
+
+// retryable reports whether err is worth retrying given whether the request is idempotent.
+func retryable(err error, idempotent bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrRateLimited) {
+		return false
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		// the response would not change
+		return false
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		// network error, the request may or may not have been executed
+		return idempotent
+	}
+	if !apiErr.Retryable() {
+		return false
+	}
+	switch {
+	case apiErr.Code == 7 || apiErr.Code == 8:
+		return true
+	case apiErr.Code == 0 && apiErr.HTTPStatus == http.StatusTooManyRequests:
+		return true
+	}
+	return idempotent
+}
+
+// RetryError is returned when a request failed with other than an *APIError after being retried.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// noResendKey marks a context whose requests are not sent again once they may have been executed,
+// whatever the retry policy, see PlaceOrRecover.
+type noResendKey struct{}
+
+// withRetry calls send until it succeeds or the retry policy of the client gives up.
+func (b *Client) withRetry(ctx context.Context, endpoint string, idempotent bool, send func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := send()
+		if err == nil {
+			return nil
+		}
+
+		var wait time.Duration
+		retry := false
+		if b.retry != nil {
+			wait, retry = b.retry.Retry(RetryAttempt{Endpoint: endpoint, Idempotent: idempotent, Attempt: attempt, Err: err})
+		}
+		if retry && ctx.Value(noResendKey{}) != nil && outcomeUnknown(err) {
+			retry = false
+		}
+		if !retry {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				apiErr.Attempts = attempt
+				return err
+			}
+			if attempt > 1 {
+				return &RetryError{Attempts: attempt, Err: err}
+			}
+			return err
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if !outcomeUnknown(err) {
+				// no attempt may have been executed
+				return &internal.NotSentError{Err: ctx.Err()}
+			}
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package bitkub_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+func failingServer(t *testing.T, failures int32, failure func(w http.ResponseWriter), success string) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			failure(w)
+			return
+		}
+		w.Write([]byte(success))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func serverError(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+func invalidTimestamp(w http.ResponseWriter) {
+	w.Write([]byte(`{"error":8}`))
+}
+
+// legacySigned reports whether body carries the sig of the rest of the payload.
+func legacySigned(secret string, body []byte) bool {
+	payload := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return false
+	}
+	sig, _ := payload["sig"].(string)
+	delete(payload, "sig")
+	signed, _ := json.Marshal(payload)
+
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(signed)
+	return sig == hex.EncodeToString(h.Sum(nil))
+}
+
+func fastRetry(maxAttempts int) bitkub.Option {
+	return bitkub.WithRetryPolicy(&bitkub.BackoffPolicy{MaxAttempts: maxAttempts, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+}
+
+func TestRetryPublic(t *testing.T) {
+	srv, calls := failingServer(t, 2, serverError, `{"error":0,"result":[{"id":1,"symbol":"THB_BTC","info":"Thai Baht to Bitcoin"}]}`)
+
+	api := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL), fastRetry(3))
+	symbols, err := api.GetMarketSymbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 1 || *calls != 3 {
+		t.Fatalf("unexpected result %+v after %d calls", symbols, *calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	srv, calls := failingServer(t, 10, serverError, `{}`)
+
+	api := bitkub.NewClient("", "", bitkub.WithBaseURL(srv.URL), fastRetry(3))
+	_, err := api.GetMarketSymbols()
+
+	var apiErr *bitkub.APIError
+	if !errors.As(err, &apiErr) || apiErr.Attempts != 3 || *calls != 3 {
+		t.Fatalf("expected APIError after 3 attempts, got %v after %d calls", err, *calls)
+	}
+}
+
+func TestRetryPlaceBid(t *testing.T) {
+	order := `{"error":0,"result":{"id":1,"hash":"fwQ6dnQWQq71S9vZ9PNzX59MF28","typ":"limit","amt":1000,"rat":15000,"fee":2.5,"cre":2.5,"rec":0.06666666,"ts":1533834547}}`
+
+	t.Run("server error without client id", func(t *testing.T) {
+		srv, calls := failingServer(t, 1, serverError, order)
+		api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL), fastRetry(3))
+		if _, err := api.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, decimal.NewFromInt(1000), decimal.NewFromInt(15000)); err == nil || *calls != 1 {
+			t.Fatalf("expected no retry, got %v after %d calls", err, *calls)
+		}
+	})
+
+	t.Run("server error with client id", func(t *testing.T) {
+		srv, calls := failingServer(t, 1, serverError, order)
+		api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL), fastRetry(3))
+		if _, err := api.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, decimal.NewFromInt(1000), decimal.NewFromInt(15000), "my-order-1"); err == nil || *calls != 1 {
+			t.Fatalf("expected no retry, got %v after %d calls", err, *calls)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		srv, calls := failingServer(t, 1, func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) }, order)
+		api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL), bitkub.WithRateLimiter(nil), fastRetry(3))
+		if _, err := api.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, decimal.NewFromInt(1000), decimal.NewFromInt(15000)); err != nil || *calls != 2 {
+			t.Fatalf("expected retry, got %v after %d calls", err, *calls)
+		}
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		var calls int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			switch {
+			case !legacySigned("secret", body):
+				w.Write([]byte(`{"error":6}`))
+			case atomic.AddInt32(&calls, 1) == 1:
+				invalidTimestamp(w)
+			default:
+				w.Write([]byte(order))
+			}
+		}))
+		defer srv.Close()
+
+		api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL), fastRetry(3))
+		if _, err := api.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, decimal.NewFromInt(1000), decimal.NewFromInt(15000)); err != nil || calls != 2 {
+			t.Fatalf("expected retry signed again, got %v after %d calls", err, calls)
+		}
+	})
+}
@@ -0,0 +1,690 @@
+package bitkub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+const (
+	tradeStreamPrefix  = "market.trade."
+	tickerStreamPrefix = "market.ticker."
+
+	streamEndpoint = "/websocket-api"
+	tradeDedupSize = 1000
+)
+
+var (
+	errNoStream      = errors.New("no stream subscribed")
+	errStreamRestart = errors.New("subscriptions changed")
+)
+
+// TradeStream returns the name of the trade stream of symbol, e.g. market.trade.thb_btc.
+func TradeStream(symbol string) string {
+	return tradeStreamPrefix + strings.ToLower(symbol)
+}
+
+// TickerStream returns the name of the ticker stream of symbol, e.g. market.ticker.thb_btc.
+func TickerStream(symbol string) string {
+	return tickerStreamPrefix + strings.ToLower(symbol)
+}
+
+// StreamState is the connection state of a stream.
+type StreamState int
+
+const (
+	StreamConnecting   StreamState = iota // dialing, Attempt counts the attempts since the last connection
+	StreamConnected                       // connected and subscribed
+	StreamDisconnected                    // connection lost or failed, reconnecting after Wait
+	StreamClosed                          // Run returned with Err
+)
+
+func (s StreamState) String() string {
+	switch s {
+	case StreamConnecting:
+		return "connecting"
+	case StreamConnected:
+		return "connected"
+	case StreamDisconnected:
+		return "disconnected"
+	case StreamClosed:
+		return "closed"
+	}
+	return fmt.Sprintf("StreamState(%d)", int(s))
+}
+
+// StreamStateEvent reports a change of the connection state of a stream.
+type StreamStateEvent struct {
+	State   StreamState
+	Attempt int           // connection attempt, starting at 1
+	Err     error         // cause of StreamDisconnected and StreamClosed, nil when the subscriptions changed
+	Wait    time.Duration // delay before reconnecting after StreamDisconnected
+}
+
+type streamConfig struct {
+	reconnect    RetryPolicy
+	pingInterval time.Duration
+	readTimeout  time.Duration
+	backfill     int
+	tokenRefresh time.Duration
+	bookDepth    int
+}
+
+// StreamOption configures a stream.
+type StreamOption func(*streamConfig)
+
+// WithStreamReconnect sets the policy deciding whether and when a dropped connection is opened again.
+// It defaults to unlimited attempts with exponential backoff from 500ms up to 30s, nil disables reconnecting.
+func WithStreamReconnect(policy RetryPolicy) StreamOption {
+	return func(c *streamConfig) {
+		c.reconnect = policy
+	}
+}
+
+// WithStreamHeartbeat sends a ping every pingInterval and drops the connection when nothing, pongs
+// included, was received for readTimeout. It defaults to 15s and 45s, zero values disable either.
+func WithStreamHeartbeat(pingInterval, readTimeout time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		c.pingInterval = pingInterval
+		c.readTimeout = readTimeout
+	}
+}
+
+// WithTradeBackfill sets how many recent trades are fetched with GetMarketTrades after a reconnect
+// to fill the gap in the trade streams. It defaults to 100, zero disables backfilling.
+func WithTradeBackfill(limit int) StreamOption {
+	return func(c *streamConfig) {
+		c.backfill = limit
+	}
+}
+
+// WithTokenRefresh sets how often the private stream fetches a new token with GetWebSocketToken
+// and authenticates again. It defaults to 30m, zero only fetches a token when connecting.
+func WithTokenRefresh(interval time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		c.tokenRefresh = interval
+	}
+}
+
+// WithBookDepth sets how many orders of each side are fetched with GetMarketBooks to seed an order book.
+// It defaults to 200.
+func WithBookDepth(limit int) StreamOption {
+	return func(c *streamConfig) {
+		c.bookDepth = limit
+	}
+}
+
+func newStreamConfig(opts []StreamOption) streamConfig {
+	c := streamConfig{
+		reconnect: &BackoffPolicy{
+			MaxAttempts: math.MaxInt32,
+			BaseDelay:   500 * time.Millisecond,
+			MaxDelay:    30 * time.Second,
+		},
+		pingInterval: 15 * time.Second,
+		readTimeout:  45 * time.Second,
+		backfill:     100,
+		tokenRefresh: 30 * time.Minute,
+		bookDepth:    200,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// streamRunner keeps a WebSocket connection open: it reconnects according to the reconnect policy,
+// sends pings, enforces the read timeout and reports the connection state.
+type streamRunner struct {
+	config streamConfig
+
+	dial      func(ctx context.Context) (*internal.WSConn, error)
+	connected func(ctx context.Context, conn *internal.WSConn, reconnected bool) error // called once connected, before reading, with a ctx done when the connection ends
+	handle    func(data []byte)
+
+	mu      sync.Mutex
+	onState func(StreamStateEvent)
+	conn    *internal.WSConn
+	restart bool
+	dropErr error
+}
+
+func (r *streamRunner) setOnState(handler func(StreamStateEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onState = handler
+}
+
+func (r *streamRunner) state(event StreamStateEvent) {
+	r.mu.Lock()
+	handler := r.onState
+	r.mu.Unlock()
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// restartNow closes the current connection, which is opened again right away.
+func (r *streamRunner) restartNow() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.restart = true
+	if r.conn != nil {
+		r.conn.Close()
+	}
+}
+
+// drop closes the current connection because of err, it is opened again according to the reconnect
+// policy unless err is a *StreamAuthError.
+func (r *streamRunner) drop(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn != nil {
+		r.dropErr = err
+		r.conn.Close()
+	}
+}
+
+func (r *streamRunner) run(ctx context.Context) error {
+	attempt := 0
+	reconnected := false
+	for {
+		r.state(StreamStateEvent{State: StreamConnecting, Attempt: attempt + 1})
+		connected, err := r.runOnce(ctx, reconnected)
+		if connected {
+			attempt = 0
+			reconnected = true
+		}
+		if ctx.Err() != nil {
+			r.state(StreamStateEvent{State: StreamClosed, Err: ctx.Err()})
+			return ctx.Err()
+		}
+		var authErr *StreamAuthError
+		if errors.Is(err, errNoStream) || errors.As(err, &authErr) {
+			// reconnecting would not help
+			r.state(StreamStateEvent{State: StreamClosed, Err: err})
+			return err
+		}
+
+		r.mu.Lock()
+		restart := r.restart
+		r.restart = false
+		r.mu.Unlock()
+		if restart {
+			r.state(StreamStateEvent{State: StreamDisconnected})
+			continue
+		}
+
+		attempt++
+		wait, retry := time.Duration(0), false
+		if r.config.reconnect != nil {
+			wait, retry = r.config.reconnect.Retry(RetryAttempt{Endpoint: streamEndpoint, Idempotent: true, Attempt: attempt, Err: err})
+		}
+		if !retry {
+			r.state(StreamStateEvent{State: StreamClosed, Attempt: attempt, Err: err})
+			return err
+		}
+		r.state(StreamStateEvent{State: StreamDisconnected, Attempt: attempt, Err: err, Wait: wait})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			r.state(StreamStateEvent{State: StreamClosed, Err: ctx.Err()})
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// runOnce connects and reads until the connection fails. It reports whether the connection was established.
+func (r *streamRunner) runOnce(ctx context.Context, reconnected bool) (bool, error) {
+	r.mu.Lock()
+	r.restart = false
+	r.dropErr = nil
+	r.mu.Unlock()
+
+	conn, err := r.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	if r.restart {
+		r.mu.Unlock()
+		conn.Close()
+		return false, errStreamRestart
+	}
+	r.conn = conn
+	r.mu.Unlock()
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		conn.Close()
+		r.mu.Lock()
+		r.conn = nil
+		r.mu.Unlock()
+	}()
+	go r.heartbeat(connCtx, conn)
+
+	readTimeout := r.config.readTimeout
+	if readTimeout > 0 {
+		conn.OnPong = func([]byte) {
+			conn.SetReadDeadline(time.Now().Add(readTimeout))
+		}
+	}
+
+	if r.connected != nil {
+		if err := r.connected(connCtx, conn, reconnected); err != nil {
+			return false, err
+		}
+	}
+	r.state(StreamStateEvent{State: StreamConnected})
+
+	for {
+		if readTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(readTimeout))
+		}
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			r.mu.Lock()
+			if r.dropErr != nil {
+				err = r.dropErr
+			}
+			r.mu.Unlock()
+			return true, err
+		}
+		r.handle(data)
+	}
+}
+
+// heartbeat sends pings until ctx is done, then closes the connection. A failed ping closes it right away.
+func (r *streamRunner) heartbeat(ctx context.Context, conn *internal.WSConn) {
+	defer conn.Close()
+
+	var tick <-chan time.Time
+	if r.config.pingInterval > 0 {
+		ticker := time.NewTicker(r.config.pingInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			if err := conn.WriteMessage(internal.OpPing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// tradeKey matches a trade of GetMarketTrades, which has no transaction id, with the trades
+// delivered. Distinct trades may share a key.
+type tradeKey struct {
+	timestamp    int64
+	rate, amount string
+}
+
+// deliveredTrade is a trade remembered by a tradeCursor.
+type deliveredTrade struct {
+	txn     string
+	key     tradeKey
+	matched bool // a trade of the stream which was delivered before without transaction id
+}
+
+// tradeCursor remembers the latest trades delivered on a trade stream. Trades of the stream are
+// identified by their transaction id. Trades without one, e.g. of GetMarketTrades, are matched by
+// key: within a batch of them, the nth trade of a key is a duplicate when at least n trades of that
+// key were delivered. A trade of the stream is a duplicate of a trade of the same key delivered
+// without transaction id.
+type tradeCursor struct {
+	last      int64
+	txns      map[string]bool
+	delivered map[tradeKey]int
+	unnamed   map[tradeKey]int // trades delivered without transaction id and not matched by the stream yet
+	batch     map[tradeKey]int // trades of each key in the current batch
+	order     []deliveredTrade
+}
+
+func newTradeCursor() *tradeCursor {
+	return &tradeCursor{
+		txns:      map[string]bool{},
+		delivered: map[tradeKey]int{},
+		unnamed:   map[tradeKey]int{},
+		batch:     map[tradeKey]int{},
+	}
+}
+
+// startBatch starts a batch of trades without transaction id, e.g. of a call of GetMarketTrades.
+func (c *tradeCursor) startBatch() {
+	c.batch = map[tradeKey]int{}
+}
+
+// add reports whether the trade was not delivered yet.
+func (c *tradeCursor) add(e model.TradeEvent) bool {
+	key := tradeKey{timestamp: e.Timestamp, rate: e.Rate.String(), amount: e.Amount.String()}
+	if e.TxnID != "" {
+		if c.txns[e.TxnID] {
+			return false
+		}
+		// a trade of the stream ends the trades fetched before it
+		c.startBatch()
+		if c.unnamed[key] > 0 {
+			c.unnamed[key]--
+			c.remember(deliveredTrade{txn: e.TxnID, key: key, matched: true})
+			return false
+		}
+	} else {
+		c.batch[key]++
+		if c.batch[key] <= c.delivered[key] {
+			return false
+		}
+		c.unnamed[key]++
+	}
+
+	c.delivered[key]++
+	c.remember(deliveredTrade{txn: e.TxnID, key: key})
+	if e.Timestamp > c.last {
+		c.last = e.Timestamp
+	}
+	return true
+}
+
+// remember adds a trade, forgetting the oldest one beyond tradeDedupSize.
+func (c *tradeCursor) remember(trade deliveredTrade) {
+	if trade.txn != "" {
+		c.txns[trade.txn] = true
+	}
+	c.order = append(c.order, trade)
+	if len(c.order) <= tradeDedupSize {
+		return
+	}
+
+	oldest := c.order[0]
+	c.order = c.order[1:]
+	delete(c.txns, oldest.txn)
+	if oldest.matched {
+		return
+	}
+	if c.delivered[oldest.key]--; c.delivered[oldest.key] <= 0 {
+		delete(c.delivered, oldest.key)
+	}
+	if oldest.txn == "" && c.unnamed[oldest.key] > 0 {
+		if c.unnamed[oldest.key]--; c.unnamed[oldest.key] == 0 {
+			delete(c.unnamed, oldest.key)
+		}
+	}
+}
+
+// MarketStream delivers the public market streams of Bitkub over a single WebSocket connection.
+// Handlers are called one message at a time from the goroutine running Run, so they should not block.
+//
+// Dropped connections are detected with pings and a read timeout and opened again with all
+// subscriptions. Trades missed in between are fetched with GetMarketTrades, and trades are
+// de-duplicated by transaction id, or by timestamp, rate and amount for the fetched trades which
+// have none, so that each trade handler sees every trade once.
+type MarketStream struct {
+	client *Client
+	runner *streamRunner
+
+	mu      sync.Mutex
+	trades  map[string][]func(model.TradeEvent)
+	tickers map[string][]func(model.TickerEvent)
+	cursors map[string]*tradeCursor
+	onError func(error)
+}
+
+// NewMarketStream creates a stream using the WebSocket URL and user agent of the client.
+// Subscribe to at least one stream before calling Run.
+func (b *Client) NewMarketStream(opts ...StreamOption) *MarketStream {
+	s := &MarketStream{
+		client:  b,
+		trades:  map[string][]func(model.TradeEvent){},
+		tickers: map[string][]func(model.TickerEvent){},
+		cursors: map[string]*tradeCursor{},
+	}
+	s.runner = &streamRunner{
+		config:    newStreamConfig(opts),
+		dial:      s.dial,
+		connected: s.backfill,
+		handle:    s.dispatch,
+	}
+	return s
+}
+
+// SubscribeTrades calls handler for each trade of symbol. Subscribing while Run is connected reconnects with the new streams.
+func (s *MarketStream) SubscribeTrades(symbol string, handler func(model.TradeEvent)) {
+	s.mu.Lock()
+	name := TradeStream(symbol)
+	s.trades[name] = append(s.trades[name], handler)
+	if s.cursors[name] == nil {
+		s.cursors[name] = newTradeCursor()
+	}
+	s.mu.Unlock()
+	s.runner.restartNow()
+}
+
+// SubscribeTicker calls handler for each ticker update of symbol.
+func (s *MarketStream) SubscribeTicker(symbol string, handler func(model.TickerEvent)) {
+	s.mu.Lock()
+	name := TickerStream(symbol)
+	s.tickers[name] = append(s.tickers[name], handler)
+	s.mu.Unlock()
+	s.runner.restartNow()
+}
+
+// Unsubscribe removes all handlers of the given stream, e.g. TradeStream("thb_btc").
+func (s *MarketStream) Unsubscribe(stream string) {
+	s.mu.Lock()
+	delete(s.trades, stream)
+	delete(s.tickers, stream)
+	delete(s.cursors, stream)
+	s.mu.Unlock()
+	s.runner.restartNow()
+}
+
+// OnError sets the handler of messages which cannot be decoded and of failed backfills. Such messages are skipped.
+func (s *MarketStream) OnError(handler func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = handler
+}
+
+// OnStateChange sets the handler of connection state changes, e.g. to pause trading while disconnected.
+func (s *MarketStream) OnStateChange(handler func(StreamStateEvent)) {
+	s.runner.setOnState(handler)
+}
+
+// Streams returns the names of the subscribed streams.
+func (s *MarketStream) Streams() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streamsLocked()
+}
+
+func (s *MarketStream) streamsLocked() []string {
+	streams := []string{}
+	for name := range s.trades {
+		streams = append(streams, name)
+	}
+	for name := range s.tickers {
+		streams = append(streams, name)
+	}
+	sort.Strings(streams)
+	return streams
+}
+
+// Run connects and delivers messages until ctx is done or the reconnect policy gives up.
+func (s *MarketStream) Run(ctx context.Context) error {
+	return s.runner.run(ctx)
+}
+
+func (s *MarketStream) dial(ctx context.Context) (*internal.WSConn, error) {
+	streams := s.Streams()
+	if len(streams) == 0 {
+		return nil, errNoStream
+	}
+	return s.client.dialWebSocket(ctx, "/"+strings.Join(streams, ","))
+}
+
+// backfill delivers the trades made while the stream was disconnected.
+func (s *MarketStream) backfill(ctx context.Context, conn *internal.WSConn, reconnected bool) error {
+	limit := s.runner.config.backfill
+	if !reconnected || limit <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	since := map[string]int64{}
+	for name, cursor := range s.cursors {
+		if cursor.last > 0 {
+			since[name] = cursor.last
+		}
+	}
+	s.mu.Unlock()
+
+	for name, last := range since {
+		symbol := strings.ToUpper(strings.TrimPrefix(name, tradeStreamPrefix))
+		trades, err := s.client.GetMarketTradesCtx(ctx, symbol, limit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.error(fmt.Errorf("backfill %s: %w", name, err))
+			continue
+		}
+
+		// trades come newest first
+		for i, j := 0, len(trades)-1; i < j; i, j = i+1, j-1 {
+			trades[i], trades[j] = trades[j], trades[i]
+		}
+		sort.SliceStable(trades, func(i, j int) bool { return trades[i].Timestamp < trades[j].Timestamp })
+
+		s.mu.Lock()
+		if cursor := s.cursors[name]; cursor != nil {
+			cursor.startBatch()
+		}
+		s.mu.Unlock()
+		for _, trade := range trades {
+			if int64(trade.Timestamp) < last {
+				continue
+			}
+			s.deliverTrade(model.TradeEvent{
+				Stream:     name,
+				Symbol:     symbol,
+				Rate:       trade.Rate,
+				Amount:     trade.Amount,
+				Timestamp:  int64(trade.Timestamp),
+				Backfilled: true,
+			})
+		}
+	}
+	return nil
+}
+
+// dispatch decodes a message, which may hold several JSON objects, and calls the handlers of their stream.
+func (s *MarketStream) dispatch(data []byte) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return
+		} else if err != nil {
+			s.error(fmt.Errorf("decode stream message: %w", err))
+			return
+		}
+
+		head := struct {
+			Stream string `json:"stream"`
+		}{}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			s.error(fmt.Errorf("decode stream message: %w", err))
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(head.Stream, tradeStreamPrefix):
+			event := model.TradeEvent{}
+			if err := json.Unmarshal(raw, &event); err != nil {
+				s.error(fmt.Errorf("decode %s: %w", head.Stream, err))
+				continue
+			}
+			s.deliverTrade(event)
+		case strings.HasPrefix(head.Stream, tickerStreamPrefix):
+			event := model.TickerEvent{}
+			if err := json.Unmarshal(raw, &event); err != nil {
+				s.error(fmt.Errorf("decode %s: %w", head.Stream, err))
+				continue
+			}
+			s.mu.Lock()
+			handlers := s.tickers[head.Stream]
+			s.mu.Unlock()
+			for _, handler := range handlers {
+				handler(event)
+			}
+		}
+	}
+}
+
+func (s *MarketStream) deliverTrade(event model.TradeEvent) {
+	s.mu.Lock()
+	handlers := s.trades[event.Stream]
+	fresh := false
+	if cursor := s.cursors[event.Stream]; cursor != nil {
+		fresh = cursor.add(event)
+	}
+	s.mu.Unlock()
+	if !fresh {
+		return
+	}
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+func (s *MarketStream) error(err error) {
+	s.mu.Lock()
+	handler := s.onError
+	s.mu.Unlock()
+	if handler != nil {
+		handler(err)
+	}
+}
+
+// dialWebSocket opens a WebSocket connection to path relative to the WebSocket URL.
+func (b *Client) dialWebSocket(ctx context.Context, path string) (*internal.WSConn, error) {
+	wsURL, userAgent := b.wsURL, b.userAgent
+	if wsURL == "" {
+		wsURL = defaultWebSocketURL
+	}
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+
+	timeout := b.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	headers := map[string]string{"User-Agent": userAgent}
+	for k, v := range b.headers {
+		headers[k] = v
+	}
+	return internal.DialWebSocket(ctx, wsURL+path, headers)
+}
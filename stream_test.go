@@ -0,0 +1,251 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/internal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// streamServer serves WebSocket connections with handler and returns a client pointed at it,
+// together with the mux serving the REST endpoints.
+func streamServer(t *testing.T, handler func(path string, conn *internal.WSConn)) (*bitkub.Client, *http.ServeMux) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/websocket-api/", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := internal.UpgradeWebSocket(w, r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		handler(r.URL.Path, conn)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	api := bitkub.NewClient("", "",
+		bitkub.WithBaseURL(srv.URL),
+		bitkub.WithWebSocketURL("ws"+strings.TrimPrefix(srv.URL, "http")+"/websocket-api"),
+		bitkub.WithRateLimiter(nil),
+	)
+	return api, mux
+}
+
+// fastReconnect reconnects right away up to n times.
+func fastReconnect(n int) bitkub.StreamOption {
+	return bitkub.WithStreamReconnect(&bitkub.BackoffPolicy{MaxAttempts: n, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+}
+
+func TestMarketStream(t *testing.T) {
+	api, _ := streamServer(t, func(path string, conn *internal.WSConn) {
+		if path != "/websocket-api/market.ticker.thb_eth,market.trade.thb_btc" {
+			t.Errorf("unexpected path %s", path)
+			return
+		}
+		conn.WriteMessage(internal.OpText, []byte(`{"stream":"market.trade.thb_btc","sym":"THB_BTC","txn":"BTCSELL0000074282","rat":"2150000.55","amt":0.00012345,"bid":2048451,"sid":2924729,"ts":1542268567}
+{"stream":"market.ticker.thb_eth","id":2,"last":98000.5,"lowestAsk":98001,"highestBid":98000,"percentChange":1.91,"baseVolume":71.02603946}`))
+		conn.WriteMessage(internal.OpText, []byte(`{"stream":"market.trade.thb_btc","rat":"oops"}`))
+		conn.WriteMessage(internal.OpText, []byte(`{"stream":"market.trade.thb_btc","txn":"BTCBUY0000074283","rat":2150001,"amt":1,"ts":1542268568}`))
+		conn.ReadMessage()
+	})
+
+	stream := api.NewMarketStream()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		trades  []model.TradeEvent
+		tickers []model.TickerEvent
+		errs    []error
+	)
+	stream.SubscribeTrades("THB_BTC", func(e model.TradeEvent) {
+		trades = append(trades, e)
+		if len(trades) == 2 {
+			cancel()
+		}
+	})
+	stream.SubscribeTicker("thb_eth", func(e model.TickerEvent) { tickers = append(tickers, e) })
+	stream.OnError(func(err error) { errs = append(errs, err) })
+
+	if err := stream.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if len(trades) != 2 || trades[0].TxnID != "BTCSELL0000074282" || trades[0].Rate.String() != "2150000.55" || trades[0].Amount.String() != "0.00012345" || trades[0].SellOrderID != 2924729 {
+		t.Fatalf("unexpected trades %+v", trades)
+	}
+	if len(tickers) != 1 || tickers[0].ID != 2 || tickers[0].Last.String() != "98000.5" {
+		t.Fatalf("unexpected tickers %+v", tickers)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "market.trade.thb_btc") {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
+
+func TestMarketStreamSubscribeWhileRunning(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	api, _ := streamServer(t, func(path string, conn *internal.WSConn) {
+		mu.Lock()
+		paths = append(paths, path)
+		mu.Unlock()
+		for _, stream := range strings.Split(strings.TrimPrefix(path, "/websocket-api/"), ",") {
+			conn.WriteMessage(internal.OpText, []byte(`{"stream":"`+stream+`","rat":1,"amt":1}`))
+		}
+		conn.ReadMessage()
+	})
+
+	stream := api.NewMarketStream()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream.SubscribeTrades("thb_btc", func(e model.TradeEvent) {
+		if len(stream.Streams()) == 1 {
+			stream.SubscribeTrades("thb_eth", func(e model.TradeEvent) { cancel() })
+		}
+	})
+	if err := stream.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) != 2 || paths[1] != "/websocket-api/market.trade.thb_btc,market.trade.thb_eth" {
+		t.Fatalf("unexpected connections %v", paths)
+	}
+}
+
+func TestMarketStreamNoSubscription(t *testing.T) {
+	stream := bitkub.NewClient("", "").NewMarketStream()
+	if err := stream.Run(context.Background()); err == nil {
+		t.Fatal("expected an error without subscription")
+	}
+}
+
+func TestMarketStreamReconnectBackfill(t *testing.T) {
+	var connections int32
+	api, mux := streamServer(t, func(path string, conn *internal.WSConn) {
+		switch atomic.AddInt32(&connections, 1) {
+		case 1:
+			conn.WriteMessage(internal.OpText, []byte(`{"stream":"market.trade.thb_btc","txn":"A","rat":100,"amt":1,"ts":1000}`))
+			// then close the connection
+		case 2:
+			conn.WriteMessage(internal.OpText, []byte(`{"stream":"market.trade.thb_btc","txn":"C","rat":102,"amt":1,"ts":1002}
+{"stream":"market.trade.thb_btc","txn":"D","rat":103,"amt":1,"ts":1003}
+{"stream":"market.trade.thb_btc","txn":"D","rat":103,"amt":1,"ts":1003}
+{"stream":"market.trade.thb_btc","txn":"E","rat":103,"amt":1,"ts":1003}`))
+			conn.ReadMessage()
+		}
+	})
+	mux.HandleFunc("/api/market/trades", func(w http.ResponseWriter, r *http.Request) {
+		if sym := r.URL.Query().Get("sym"); sym != "THB_BTC" {
+			t.Errorf("unexpected symbol %s", sym)
+		}
+		// newest first, overlapping both the trade before and after the gap, with two trades alike
+		w.Write([]byte(`{"error":0,"result":[[1002,102,1,"buy"],[1001,101,2,"sell"],[1001,101,1,"buy"],[1001,101,1,"buy"],[1000,100,1,"buy"],[999,99,1,"buy"]]}`))
+	})
+
+	stream := api.NewMarketStream(fastReconnect(5))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		trades []string
+		states []string
+	)
+	stream.SubscribeTrades("thb_btc", func(e model.TradeEvent) {
+		trades = append(trades, fmt.Sprintf("%d/%s/%t", e.Timestamp, e.Amount, e.Backfilled))
+		if e.TxnID == "E" {
+			cancel()
+		}
+	})
+	stream.OnStateChange(func(e bitkub.StreamStateEvent) { states = append(states, e.State.String()) })
+
+	if err := stream.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	want := []string{"1000/1/false", "1001/1/true", "1001/1/true", "1001/2/true", "1002/1/true", "1003/1/false", "1003/1/false"}
+	if strings.Join(trades, " ") != strings.Join(want, " ") {
+		t.Fatalf("got trades %v, want %v", trades, want)
+	}
+	wantStates := []string{"connecting", "connected", "disconnected", "connecting", "connected", "closed"}
+	if strings.Join(states, " ") != strings.Join(wantStates, " ") {
+		t.Fatalf("got states %v, want %v", states, wantStates)
+	}
+}
+
+func TestMarketStreamHeartbeat(t *testing.T) {
+	var connections int32
+	api, _ := streamServer(t, func(path string, conn *internal.WSConn) {
+		if atomic.AddInt32(&connections, 1) == 1 {
+			// never read, so pings are not answered
+			time.Sleep(500 * time.Millisecond)
+			return
+		}
+		conn.WriteMessage(internal.OpText, []byte(`{"stream":"market.ticker.thb_btc","last":1}`))
+		conn.ReadMessage()
+	})
+
+	stream := api.NewMarketStream(fastReconnect(5), bitkub.WithStreamHeartbeat(20*time.Millisecond, 100*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var disconnect error
+	stream.OnStateChange(func(e bitkub.StreamStateEvent) {
+		if e.State == bitkub.StreamDisconnected {
+			disconnect = e.Err
+		}
+	})
+	stream.SubscribeTicker("thb_btc", func(e model.TickerEvent) { cancel() })
+
+	start := time.Now()
+	if err := stream.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	var netErr net.Error
+	if !errors.As(disconnect, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected a read timeout, got %v", disconnect)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Fatalf("dead connection detected after %s", elapsed)
+	}
+}
+
+func TestMarketStreamGiveUp(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	api := bitkub.NewClient("", "", bitkub.WithWebSocketURL("ws"+strings.TrimPrefix(srv.URL, "http")))
+	stream := api.NewMarketStream(fastReconnect(3))
+	stream.SubscribeTrades("thb_btc", func(e model.TradeEvent) {})
+
+	attempts := 0
+	var last bitkub.StreamStateEvent
+	stream.OnStateChange(func(e bitkub.StreamStateEvent) {
+		if e.State == bitkub.StreamConnecting {
+			attempts++
+		}
+		last = e
+	})
+
+	if err := stream.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected handshake error, got %v", err)
+	}
+	if attempts != 3 || last.State != bitkub.StreamClosed || last.Err == nil {
+		t.Fatalf("unexpected %d attempts, last state %+v", attempts, last)
+	}
+}
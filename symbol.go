@@ -0,0 +1,246 @@
+package bitkub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+var (
+	// ErrUnknownSymbol is returned by order methods for a symbol missing from the symbol registry.
+	ErrUnknownSymbol = errors.New("unknown symbol")
+	// ErrInvalidOrder is returned by order methods for an order breaking the rules of its symbol.
+	ErrInvalidOrder = errors.New("invalid order")
+)
+
+// orderError is an ErrInvalidOrder also matching the error code Bitkub would have answered with.
+type orderError struct {
+	code *APIError
+	msg  string
+}
+
+func (e *orderError) Error() string {
+	return ErrInvalidOrder.Error() + ": " + e.msg
+}
+
+func (e *orderError) Is(target error) bool {
+	return target == ErrInvalidOrder || errors.Is(e.code, target)
+}
+
+// quoteAssets are the assets symbols are quoted in.
+var quoteAssets = map[string]bool{"THB": true}
+
+// Symbol is a trading pair, e.g. BTC quoted in THB.
+type Symbol struct {
+	Base  string // e.g. BTC
+	Quote string // e.g. THB
+}
+
+// ParseSymbol parses a symbol in either the legacy form quoted first, e.g. THB_BTC, or the v3 form
+// quoted last, e.g. btc_thb or BTC_THB.
+func ParseSymbol(s string) (Symbol, error) {
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(s)), "_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Symbol{}, fmt.Errorf("invalid symbol %q", s)
+	}
+	if quoteAssets[parts[0]] && !quoteAssets[parts[1]] {
+		return Symbol{Base: parts[1], Quote: parts[0]}, nil
+	}
+	return Symbol{Base: parts[0], Quote: parts[1]}, nil
+}
+
+// Legacy returns the form of the v1 endpoints, e.g. THB_BTC.
+func (s Symbol) Legacy() string {
+	return s.Quote + "_" + s.Base
+}
+
+// V3 returns the form of the v3 endpoints, e.g. btc_thb.
+func (s Symbol) V3() string {
+	return strings.ToLower(s.Base + "_" + s.Quote)
+}
+
+// String returns the symbol quoted last, e.g. BTC_THB.
+func (s Symbol) String() string {
+	return s.Base + "_" + s.Quote
+}
+
+// SymbolRules are the identifiers and trading rules of a symbol.
+type SymbolRules struct {
+	Symbol Symbol
+	ID     int    // id of GetMarketSymbols, also used by the orderbook stream
+	Info   string // description, e.g. Thai Baht to Bitcoin
+
+	// The rules below are only set when HasRules is true, i.e. the symbol is listed by GetSymbolInfo.
+	HasRules        bool
+	PricePrecision  int32           // decimal places of rates
+	AmountPrecision int32           // decimal places of amounts of the base asset
+	QuotePrecision  int32           // decimal places of amounts of the quote asset
+	MinQuoteSize    decimal.Decimal // minimum value of an order in the quote asset
+	Active          bool            // trading is open
+	FreezeBuy       bool
+	FreezeSell      bool
+}
+
+// SymbolRegistry caches the symbols of GetMarketSymbols and their rules from GetSymbolInfo.
+// It is safe for concurrent use.
+type SymbolRegistry struct {
+	mu      sync.RWMutex
+	loaded  bool
+	symbols map[Symbol]SymbolRules
+}
+
+// NewSymbolRegistry creates an empty registry, see Load and WithSymbolRegistry.
+func NewSymbolRegistry() *SymbolRegistry {
+	return &SymbolRegistry{symbols: map[Symbol]SymbolRules{}}
+}
+
+// Load replaces the symbols of the registry with those listed by api, e.g. to pick up a new listing.
+func (r *SymbolRegistry) Load(ctx context.Context, api *Client) error {
+	markets, err := api.GetMarketSymbolsCtx(ctx)
+	if err != nil {
+		return err
+	}
+	infos, err := api.GetSymbolInfoCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	symbols := map[Symbol]SymbolRules{}
+	for _, m := range markets {
+		symbol, err := ParseSymbol(m.Symbol)
+		if err != nil {
+			continue
+		}
+		symbols[symbol] = SymbolRules{Symbol: symbol, ID: m.ID, Info: m.Info}
+	}
+	for _, info := range infos {
+		symbol, err := ParseSymbol(info.Symbol)
+		if err != nil {
+			continue
+		}
+		rules := symbols[symbol]
+		rules.Symbol = symbol
+		if rules.ID == 0 {
+			rules.ID = info.PairingID
+		}
+		if rules.Info == "" {
+			rules.Info = info.Description
+		}
+		rules.HasRules = true
+		rules.PricePrecision = info.PriceScale
+		rules.AmountPrecision = info.BaseAssetScale
+		rules.QuotePrecision = info.QuoteAssetScale
+		rules.MinQuoteSize = info.MinQuoteSize
+		rules.Active = info.Status == "active"
+		rules.FreezeBuy = info.FreezeBuy
+		rules.FreezeSell = info.FreezeSell
+		symbols[symbol] = rules
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.symbols = symbols
+	r.loaded = true
+	return nil
+}
+
+// Loaded reports whether Load succeeded at least once.
+func (r *SymbolRegistry) Loaded() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.loaded
+}
+
+// Lookup returns the rules of symbol given in any form.
+func (r *SymbolRegistry) Lookup(symbol string) (SymbolRules, error) {
+	s, err := ParseSymbol(symbol)
+	if err != nil {
+		return SymbolRules{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rules, ok := r.symbols[s]
+	if !ok {
+		return SymbolRules{}, fmt.Errorf("%w %s", ErrUnknownSymbol, symbol)
+	}
+	return rules, nil
+}
+
+// Symbols returns the rules of all symbols, sorted by symbol.
+func (r *SymbolRegistry) Symbols() []SymbolRules {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	symbols := make([]SymbolRules, 0, len(r.symbols))
+	for _, rules := range r.symbols {
+		symbols = append(symbols, rules)
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Symbol.String() < symbols[j].Symbol.String() })
+	return symbols
+}
+
+// ValidateOrder checks an order of amount at rate against the rules. The amount of a buy order is
+// the quote asset to spend, that of a sell order the base asset to sell, unless byQuote is set.
+// The rate of a market order is zero. An order below the minimum size fails with an error matching
+// both ErrInvalidOrder and ErrAmountTooLow.
+func (r SymbolRules) ValidateOrder(side string, amount, rate decimal.Decimal, byQuote bool) error {
+	if !r.HasRules {
+		return nil
+	}
+	if !r.Active {
+		return fmt.Errorf("%w: %s is not open for trading", ErrInvalidOrder, r.Symbol)
+	}
+	if (side == OrderSideBuy && r.FreezeBuy) || (side == OrderSideSell && r.FreezeSell) {
+		return fmt.Errorf("%w: %s is frozen for %s orders", ErrInvalidOrder, r.Symbol, side)
+	}
+
+	if rate.Places() > r.PricePrecision {
+		return fmt.Errorf("%w: rate %s of %s has more than %d decimal places", ErrInvalidOrder, rate, r.Symbol, r.PricePrecision)
+	}
+	quote := side == OrderSideBuy || byQuote
+	precision := r.AmountPrecision
+	if quote {
+		precision = r.QuotePrecision
+	}
+	if amount.Places() > precision {
+		return fmt.Errorf("%w: amount %s of %s has more than %d decimal places", ErrInvalidOrder, amount, r.Symbol, precision)
+	}
+
+	value := amount
+	if !quote {
+		if rate.IsZero() {
+			// the value of a market sell order is unknown
+			return nil
+		}
+		value = amount.Mul(rate)
+	}
+	if value.LessThan(r.MinQuoteSize) {
+		return &orderError{code: ErrAmountTooLow, msg: fmt.Sprintf("value %s %s of %s is below the minimum of %s", value, r.Symbol.Quote, r.Symbol, r.MinQuoteSize)}
+	}
+	return nil
+}
+
+// checkSymbol returns symbol in its legacy or v3 form after checking it against the symbol registry
+// of the client, loading the registry first if needed. Without a registry, symbol is returned as is.
+func (b *Client) checkSymbol(ctx context.Context, symbol string, v3 bool) (SymbolRules, string, error) {
+	if b.symbols == nil {
+		return SymbolRules{}, symbol, nil
+	}
+	if !b.symbols.Loaded() {
+		if err := b.symbols.Load(ctx, b); err != nil {
+			return SymbolRules{}, "", fmt.Errorf("load symbols: %w", err)
+		}
+	}
+	rules, err := b.symbols.Lookup(symbol)
+	if err != nil {
+		return SymbolRules{}, "", err
+	}
+	if v3 {
+		return rules, rules.Symbol.V3(), nil
+	}
+	return rules, rules.Symbol.Legacy(), nil
+}
@@ -0,0 +1,181 @@
+package bitkub_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+func TestParseSymbol(t *testing.T) {
+	for _, s := range []string{"THB_BTC", "btc_thb", "BTC_THB", " thb_btc "} {
+		symbol, err := bitkub.ParseSymbol(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if symbol.Base != "BTC" || symbol.Quote != "THB" || symbol.Legacy() != "THB_BTC" || symbol.V3() != "btc_thb" || symbol.String() != "BTC_THB" {
+			t.Fatalf("%s: unexpected symbol %+v", s, symbol)
+		}
+	}
+	for _, s := range []string{"", "BTC", "THB_", "A_B_C"} {
+		if _, err := bitkub.ParseSymbol(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+// symbolServer serves the symbols of symbolMux, and records the payload of orders.
+func symbolServer(t *testing.T) (*bitkub.Client, *map[string]interface{}, *int32) {
+	var (
+		payload map[string]interface{}
+		loads   int32
+	)
+	mux := symbolMux(&loads)
+	order := func(w http.ResponseWriter, r *http.Request) {
+		payload = payloadOf(r)
+		w.Write([]byte(`{"error":0,"result":{"id":1}}`))
+	}
+	mux.HandleFunc("/api/market/place-bid", order)
+	mux.HandleFunc("/api/market/place-ask", order)
+	mux.HandleFunc("/api/v3/market/place-bid", func(w http.ResponseWriter, r *http.Request) {
+		payload = payloadOf(r)
+		w.Write([]byte(`{"error":0,"result":{"id":"1"}}`))
+	})
+	mux.HandleFunc("/api/market/my-open-orders", func(w http.ResponseWriter, r *http.Request) {
+		payload = payloadOf(r)
+		w.Write([]byte(`{"error":0,"result":[]}`))
+	})
+	mux.HandleFunc("/api/v3/market/my-open-orders", func(w http.ResponseWriter, r *http.Request) {
+		payload = map[string]interface{}{"sym": r.URL.Query().Get("sym")}
+		w.Write([]byte(`{"error":0,"result":[]}`))
+	})
+	return testClient(t, mux, bitkub.WithSymbolRegistry(bitkub.NewSymbolRegistry())), &payload, &loads
+}
+
+func TestSymbolRegistry(t *testing.T) {
+	api, _, _ := symbolServer(t)
+	registry := bitkub.NewSymbolRegistry()
+	if err := registry.Load(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := registry.Lookup("btc_thb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.ID != 1 || rules.Info != "Thai Baht to Bitcoin" || !rules.HasRules || rules.AmountPrecision != 8 || rules.PricePrecision != 2 || rules.MinQuoteSize.String() != "10" || !rules.Active {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+	if rules, err := registry.Lookup("THB_NEW"); err != nil || rules.HasRules {
+		t.Fatalf("expected THB_NEW without rules, got %+v, %v", rules, err)
+	}
+	if _, err := registry.Lookup("THB_XYZ"); !errors.Is(err, bitkub.ErrUnknownSymbol) {
+		t.Fatalf("expected ErrUnknownSymbol, got %v", err)
+	}
+	if symbols := registry.Symbols(); len(symbols) != 3 || symbols[0].Symbol.String() != "BTC_THB" {
+		t.Fatalf("unexpected symbols %+v", symbols)
+	}
+}
+
+func TestOrderSymbolValidation(t *testing.T) {
+	api, payload, loads := symbolServer(t)
+	d := decimal.RequireFromString
+
+	// the v3 form is sent as THB_BTC to the v1 endpoint
+	if _, err := api.PlaceBid("btc_thb", bitkub.OrderTypeLimit, d("100"), d("1000000.5")); err != nil {
+		t.Fatal(err)
+	}
+	if (*payload)["sym"] != "THB_BTC" {
+		t.Fatalf("unexpected symbol %v", (*payload)["sym"])
+	}
+	if _, err := api.V3().PlaceBid(context.Background(), "THB_BTC", bitkub.OrderTypeLimit, d("100"), d("1000000")); err != nil {
+		t.Fatal(err)
+	}
+	if (*payload)["sym"] != "btc_thb" {
+		t.Fatalf("unexpected v3 symbol %v", (*payload)["sym"])
+	}
+	// order queries are normalized too
+	if _, err := api.GetOpenOrder("btc_thb"); err != nil {
+		t.Fatal(err)
+	}
+	if (*payload)["sym"] != "THB_BTC" {
+		t.Fatalf("unexpected open orders symbol %v", (*payload)["sym"])
+	}
+	if _, err := api.V3().GetOpenOrder(context.Background(), "THB_BTC"); err != nil {
+		t.Fatal(err)
+	}
+	if (*payload)["sym"] != "btc_thb" {
+		t.Fatalf("unexpected v3 open orders symbol %v", (*payload)["sym"])
+	}
+	// market sell orders have no known value
+	if _, err := api.PlaceAsk("THB_BTC", bitkub.OrderTypeMarket, d("0.00000001"), decimal.Zero); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := map[string]func() error{
+		"unknown symbol": func() error {
+			_, err := api.PlaceBid("THB_XYZ", bitkub.OrderTypeLimit, d("100"), d("1"))
+			return err
+		},
+		"rate precision": func() error {
+			_, err := api.PlaceBid("THB_BTC", bitkub.OrderTypeLimit, d("100"), d("1000000.001"))
+			return err
+		},
+		"amount precision": func() error {
+			_, err := api.PlaceAsk("THB_BTC", bitkub.OrderTypeLimit, d("0.000000001"), d("1000000"))
+			return err
+		},
+		"below minimum": func() error {
+			_, err := api.PlaceAsk("THB_BTC", bitkub.OrderTypeLimit, d("0.000005"), d("1000000"))
+			return err
+		},
+		"frozen": func() error {
+			_, err := api.PlaceBid("THB_ETH", bitkub.OrderTypeLimit, d("100"), d("50000"))
+			return err
+		},
+		"cancel unknown symbol": func() error {
+			return api.CancelOrder("THB_XYZ", bitkub.OrderSideBuy, "", 1)
+		},
+		"open orders of unknown symbol": func() error {
+			_, err := api.GetOpenOrder("THB_XYZ")
+			return err
+		},
+		"history of unknown symbol": func() error {
+			_, _, err := api.GetOrderHistory("THB_XYZ", 1, 10, 0, 0)
+			return err
+		},
+		"info of unknown symbol": func() error {
+			_, err := api.GetOrderInfo("THB_XYZ", bitkub.OrderSideBuy, "", 1)
+			return err
+		},
+		"v3 open orders of unknown symbol": func() error {
+			_, err := api.V3().GetOpenOrder(context.Background(), "xyz_thb")
+			return err
+		},
+		"v3 history of unknown symbol": func() error {
+			_, _, err := api.V3().GetOrderHistory(context.Background(), "xyz_thb", 1, 10, 0, 0)
+			return err
+		},
+		"v3 info of unknown symbol": func() error {
+			_, err := api.V3().GetOrderInfo(context.Background(), "xyz_thb", bitkub.OrderSideBuy, "", "1")
+			return err
+		},
+	}
+	for name, place := range invalid {
+		*payload = nil
+		err := place()
+		if !errors.Is(err, bitkub.ErrInvalidOrder) && !errors.Is(err, bitkub.ErrUnknownSymbol) {
+			t.Errorf("%s: expected a validation error, got %v", name, err)
+		}
+		if *payload != nil {
+			t.Errorf("%s: the order was sent", name)
+		}
+	}
+	if n := atomic.LoadInt32(loads); n != 1 {
+		t.Fatalf("expected the registry to be loaded once, got %d", n)
+	}
+}
@@ -0,0 +1,29 @@
+package bitkub
+
+import (
+	"context"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// Trader is the trading API of the account, implemented by Client for live trading and by
+// PaperTrader for simulated trading, so a strategy can run against either.
+type Trader interface {
+	PlaceOrder(req *OrderRequest) (*model.Order, error)
+	PlaceOrderCtx(ctx context.Context, req *OrderRequest) (*model.Order, error)
+	PlaceBid(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error)
+	PlaceBidCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error)
+	PlaceAsk(symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error)
+	PlaceAskCtx(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.Order, error)
+	CancelOrder(symbol, side, hash string, id int) error
+	CancelOrderCtx(ctx context.Context, symbol, side, hash string, id int) error
+	GetOpenOrder(symbol string) ([]model.OpenOrder, error)
+	GetOpenOrderCtx(ctx context.Context, symbol string) ([]model.OpenOrder, error)
+	GetBalances() (map[string]model.Balance, error)
+	GetBalancesCtx(ctx context.Context) (map[string]model.Balance, error)
+	GetOrderHistory(symbol string, page, limit int, start, end int64) ([]model.OrderHistory, *model.OrderHistoryPagination, error)
+	GetOrderHistoryCtx(ctx context.Context, symbol string, page, limit int, start, end int64) ([]model.OrderHistory, *model.OrderHistoryPagination, error)
+}
+
+var _ Trader = (*Client)(nil)
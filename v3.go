@@ -0,0 +1,364 @@
+package bitkub
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ChanasinP/bitkub-go/decimal"
+	"github.com/ChanasinP/bitkub-go/model"
+)
+
+// ClientV3 calls the Bitkub v3 secure endpoints, which are signed with the X-BTK-TIMESTAMP and
+// X-BTK-SIGN headers instead of the ts and sig payload fields, and return order ids as strings.
+// It shares the configuration, rate limiter and clock of the Client it was created from.
+type ClientV3 struct {
+	c *Client
+}
+
+// V3 returns a client for the v3 secure endpoints.
+func (b *Client) V3() *ClientV3 {
+	return &ClientV3{c: b}
+}
+
+func (v *ClientV3) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return v.c.secure(ctx, "GET", path, nil, out, v.c.signV3)
+}
+
+func (v *ClientV3) post(ctx context.Context, path string, query url.Values, payload map[string]interface{}, out interface{}) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return v.c.secure(ctx, "POST", path, payload, out, v.c.signV3)
+}
+
+func pageQuery(page, limit int) url.Values {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("p", strconv.Itoa(page))
+	}
+	if limit > 0 {
+		query.Set("lmt", strconv.Itoa(limit))
+	}
+	return query
+}
+
+func orderRefQuery(symbol, side, hash, id string) (url.Values, error) {
+	if hash != "" {
+		return url.Values{"hash": {hash}}, nil
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is empty")
+	}
+	if side == "" {
+		return nil, fmt.Errorf("side is empty")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("id is empty")
+	}
+	if side != OrderSideBuy && side != OrderSideSell {
+		return nil, fmt.Errorf("side is invalid")
+	}
+	return url.Values{"sym": {symbol}, "id": {id}, "sd": {side}}, nil
+}
+
+// GetServerTime Get server timestamp, in milliseconds unlike Client.GetServerTime.
+func (v *ClientV3) GetServerTime(ctx context.Context) (time.Time, error) {
+	var ts int64
+	if err := v.c.get(ctx, "/api/v3/servertime", &ts); err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ts), nil
+}
+
+// GetWallet Get user available balances.
+func (v *ClientV3) GetWallet(ctx context.Context) (model.Wallet, error) {
+	ret := model.WalletResponse{}
+	if err := v.post(ctx, "/api/v3/market/wallet", nil, nil, &ret); err != nil {
+		return nil, err
+	}
+	return ret.Result, nil
+}
+
+// GetBalances Get balances info: this includes both available and reserved balances.
+func (v *ClientV3) GetBalances(ctx context.Context) (map[string]model.Balance, error) {
+	ret := struct {
+		Result map[string]model.Balance `json:"result"`
+	}{}
+	if err := v.post(ctx, "/api/v3/market/balances", nil, nil, &ret); err != nil {
+		return nil, err
+	}
+	return ret.Result, nil
+}
+
+// PlaceOrder Create a buy or sell order, see OrderRequest. The v3 endpoints have no sell order by
+// quote amount.
+func (v *ClientV3) PlaceOrder(ctx context.Context, req *OrderRequest) (*model.OrderV3, error) {
+	order, err := req.resolve(ctx, v.c, true, v.GetBalances)
+	if err != nil {
+		return nil, err
+	}
+	if order.byQuote {
+		return nil, fmt.Errorf("a sell order by quote amount is not supported by v3")
+	}
+
+	path := "/api/v3/market/place-bid"
+	if req.side == OrderSideSell {
+		path = "/api/v3/market/place-ask"
+	}
+	if req.test {
+		path += "/test"
+	}
+
+	ret := model.OrderV3Response{}
+	if err := v.post(ctx, path, nil, order.payload(), &ret); err != nil {
+		return nil, err
+	}
+	if ret.Result.ClientID == "" {
+		ret.Result.ClientID = order.clientID
+	}
+	return &ret.Result, nil
+}
+
+// PlaceBid Create a buy order. The v3 endpoints use lower case symbols quoted last, e.g. btc_thb.
+func (v *ClientV3) PlaceBid(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.OrderV3, error) {
+	return v.PlaceOrder(ctx, newOrderRequest(OrderSideBuy, symbol, bitType, amount, rate, clientID))
+}
+
+// PlaceAsk Create a sell order.
+func (v *ClientV3) PlaceAsk(ctx context.Context, symbol, bitType string, amount, rate decimal.Decimal, clientID ...string) (*model.OrderV3, error) {
+	return v.PlaceOrder(ctx, newOrderRequest(OrderSideSell, symbol, bitType, amount, rate, clientID))
+}
+
+// CancelOrder Cancel an open order.
+func (v *ClientV3) CancelOrder(ctx context.Context, symbol, side, hash, id string) error {
+	query, err := orderRefQuery(symbol, side, hash, id)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{}
+	for k := range query {
+		payload[k] = query.Get(k)
+	}
+	if hash == "" {
+		if _, payload["sym"], err = v.c.checkSymbol(ctx, symbol, true); err != nil {
+			return err
+		}
+	}
+	return v.post(ctx, "/api/v3/market/cancel-order", nil, payload, nil)
+}
+
+// GetOpenOrder List all open orders of the given symbol.
+func (v *ClientV3) GetOpenOrder(ctx context.Context, symbol string) ([]model.OpenOrderV3, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is empty")
+	}
+	_, symbol, err := v.c.checkSymbol(ctx, symbol, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := model.OpenOrderV3Response{}
+	if err := v.get(ctx, "/api/v3/market/my-open-orders", url.Values{"sym": {symbol}}, &ret); err != nil {
+		return nil, err
+	}
+	return ret.Result, nil
+}
+
+// GetOrderHistory List all orders that have already matched.
+func (v *ClientV3) GetOrderHistory(ctx context.Context, symbol string, page, limit int, start, end int64) ([]model.OrderHistoryV3, *model.OrderHistoryPagination, error) {
+	if symbol == "" {
+		return nil, nil, fmt.Errorf("symbol is empty")
+	}
+	_, symbol, err := v.c.checkSymbol(ctx, symbol, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := pageQuery(page, limit)
+	query.Set("sym", symbol)
+	if start > 0 {
+		query.Set("start", strconv.FormatInt(start, 10))
+	}
+	if end > 0 {
+		query.Set("end", strconv.FormatInt(end, 10))
+	}
+
+	ret := model.OrderHistoryV3Response{}
+	if err := v.get(ctx, "/api/v3/market/my-order-history", query, &ret); err != nil {
+		return nil, nil, err
+	}
+	return ret.Result, &ret.Pagination, nil
+}
+
+// GetOrderInfo Get information regarding the specified order.
+func (v *ClientV3) GetOrderInfo(ctx context.Context, symbol, side, hash, id string) (*model.OrderInfoV3, error) {
+	query, err := orderRefQuery(symbol, side, hash, id)
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		if _, symbol, err = v.c.checkSymbol(ctx, symbol, true); err != nil {
+			return nil, err
+		}
+		query.Set("sym", symbol)
+	}
+
+	ret := model.OrderInfoV3Response{}
+	if err := v.get(ctx, "/api/v3/market/order-info", query, &ret); err != nil {
+		return nil, err
+	}
+	return &ret.Result, nil
+}
+
+// GetWebSocketToken Get the token for websocket authentication
+func (v *ClientV3) GetWebSocketToken(ctx context.Context) (string, error) {
+	ret := struct {
+		Result string `json:"result"`
+	}{}
+	if err := v.post(ctx, "/api/v3/market/wstoken", nil, nil, &ret); err != nil {
+		return "", err
+	}
+	return ret.Result, nil
+}
+
+// GetCryptoAddresses List all crypto addresses.
+func (v *ClientV3) GetCryptoAddresses(ctx context.Context, page, limit int) ([]model.CryptoAddress, *model.Pagination, error) {
+	ret := model.CryptoAddressResponse{}
+	if err := v.post(ctx, "/api/v3/crypto/addresses", pageQuery(page, limit), nil, &ret); err != nil {
+		return nil, nil, err
+	}
+	return ret.Result, &ret.Pagination, nil
+}
+
+// CryptoWithdraw Make a withdrawal to a trusted address on the given network.
+func (v *ClientV3) CryptoWithdraw(ctx context.Context, currency, address string, amount decimal.Decimal, memo, network string) (*model.CryptoWithdraw, error) {
+	payload, err := withdrawPayload(currency, address, amount, memo)
+	if err != nil {
+		return nil, err
+	}
+	if network == "" {
+		return nil, fmt.Errorf("network is empty")
+	}
+	payload["net"] = network
+
+	ret := model.CryptoWithdrawResponse{}
+	if err := v.post(ctx, "/api/v3/crypto/withdraw", nil, payload, &ret); err != nil {
+		return nil, err
+	}
+	return &ret.Result, nil
+}
+
+// CryptoInternalWithdraw Make a withdraw to an internal address. The destination address is not required to be a trusted address.
+func (v *ClientV3) CryptoInternalWithdraw(ctx context.Context, currency, address string, amount decimal.Decimal, memo string) (*model.CryptoWithdraw, error) {
+	payload, err := withdrawPayload(currency, address, amount, memo)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := model.CryptoWithdrawResponse{}
+	if err := v.post(ctx, "/api/v3/crypto/internal-withdraw", nil, payload, &ret); err != nil {
+		return nil, err
+	}
+	return &ret.Result, nil
+}
+
+// GetCryptoDepositHistory List crypto deposit history.
+func (v *ClientV3) GetCryptoDepositHistory(ctx context.Context, page, limit int) ([]model.CryptoDeposit, *model.Pagination, error) {
+	ret := model.CryptoDepositResponse{}
+	if err := v.post(ctx, "/api/v3/crypto/deposit-history", pageQuery(page, limit), nil, &ret); err != nil {
+		return nil, nil, err
+	}
+	return ret.Result, &ret.Pagination, nil
+}
+
+// GetCryptoWithdrawHistory List crypto withdrawal history.
+func (v *ClientV3) GetCryptoWithdrawHistory(ctx context.Context, page, limit int) ([]model.CryptoWithdraw, *model.Pagination, error) {
+	ret := model.CryptoWithdrawHistoryResponse{}
+	if err := v.post(ctx, "/api/v3/crypto/withdraw-history", pageQuery(page, limit), nil, &ret); err != nil {
+		return nil, nil, err
+	}
+	return ret.Result, &ret.Pagination, nil
+}
+
+// CryptoGenerateAddress Generate a new crypto address (will replace existing address; previous address can still be used to received funds)
+func (v *ClientV3) CryptoGenerateAddress(ctx context.Context, symbol string) ([]model.CryptoGenerateAddress, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is empty")
+	}
+
+	ret := model.CryptoGenerateAddressResponse{}
+	if err := v.post(ctx, "/api/v3/crypto/generate-address", url.Values{"sym": {symbol}}, nil, &ret); err != nil {
+		return nil, err
+	}
+	return ret.Result, nil
+}
+
+// GetBankAccounts List all approved bank accounts.
+func (v *ClientV3) GetBankAccounts(ctx context.Context, page, limit int) ([]model.BankAccount, *model.Pagination, error) {
+	ret := model.FiatAccountsResponse{}
+	if err := v.post(ctx, "/api/v3/fiat/accounts", pageQuery(page, limit), nil, &ret); err != nil {
+		return nil, nil, err
+	}
+	return ret.Result, &ret.Pagination, nil
+}
+
+// FiatWithdraw Make a withdrawal to an approved bank account.
+func (v *ClientV3) FiatWithdraw(ctx context.Context, bankID string, amount decimal.Decimal) (*model.FiatWithdraw, error) {
+	if bankID == "" {
+		return nil, fmt.Errorf("bank id is empty")
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("amount is invalid")
+	}
+
+	ret := model.FiatWithdrawResponse{}
+	if err := v.post(ctx, "/api/v3/fiat/withdraw", nil, map[string]interface{}{"id": bankID, "amt": amount.String()}, &ret); err != nil {
+		return nil, err
+	}
+	return &ret.Result, nil
+}
+
+// GetFiatDepositHistory List fiat deposit history.
+func (v *ClientV3) GetFiatDepositHistory(ctx context.Context, page, limit int) ([]model.FiatDeposit, *model.Pagination, error) {
+	ret := model.FiatDepositResponse{}
+	if err := v.post(ctx, "/api/v3/fiat/deposit-history", pageQuery(page, limit), nil, &ret); err != nil {
+		return nil, nil, err
+	}
+	return ret.Result, &ret.Pagination, nil
+}
+
+// GetFiatWithdrawHistory List fiat withdrawal history.
+func (v *ClientV3) GetFiatWithdrawHistory(ctx context.Context, page, limit int) ([]model.FiatWithdraw, *model.Pagination, error) {
+	ret := model.FiatWithdrawHistoryResponse{}
+	if err := v.post(ctx, "/api/v3/fiat/withdraw-history", pageQuery(page, limit), nil, &ret); err != nil {
+		return nil, nil, err
+	}
+	return ret.Result, &ret.Pagination, nil
+}
+
+// GetUserLimits Check deposit/withdraw limitations and usage.
+func (v *ClientV3) GetUserLimits(ctx context.Context) (*model.UserLimits, error) {
+	ret := model.UserLimitsResponse{}
+	if err := v.post(ctx, "/api/v3/user/limits", nil, nil, &ret); err != nil {
+		return nil, err
+	}
+	return &ret.Result, nil
+}
+
+// GetUserTradingCredits Check trading credit balance.
+func (v *ClientV3) GetUserTradingCredits(ctx context.Context) (decimal.Decimal, error) {
+	ret := struct {
+		Result decimal.Decimal `json:"result"`
+	}{}
+	if err := v.post(ctx, "/api/v3/user/trading-credits", nil, nil, &ret); err != nil {
+		return decimal.Zero, err
+	}
+	return ret.Result, nil
+}
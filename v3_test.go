@@ -0,0 +1,68 @@
+package bitkub_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChanasinP/bitkub-go"
+	"github.com/ChanasinP/bitkub-go/decimal"
+)
+
+func v3Server(t *testing.T, secret string, responses map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get("X-BTK-TIMESTAMP")
+
+		h := hmac.New(sha256.New, []byte(secret))
+		h.Write([]byte(ts + r.Method + r.URL.RequestURI() + string(body)))
+		if r.Header.Get("X-BTK-SIGN") != hex.EncodeToString(h.Sum(nil)) || r.Header.Get("X-BTK-APIKEY") != "key" {
+			w.Write([]byte(`{"error":6}`))
+			return
+		}
+
+		resp, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Write([]byte(resp))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestV3(t *testing.T) {
+	srv := v3Server(t, "secret", map[string]string{
+		"POST /api/v3/market/place-bid":     `{"error":0,"result":{"id":"1","hash":"fwQ6dnQWQq71S9vZ9PNzX59MF28","typ":"limit","amt":1000,"rat":15000,"fee":2.5,"cre":2.5,"rec":0.06666666,"ts":1707220636,"ci":"my-order"}}`,
+		"GET /api/v3/market/my-open-orders": `{"error":0,"result":[{"id":"2","hash":"fwQ6dnQWQPs4cbatF5Am2xCDP1J","side":"sell","type":"limit","rate":"15000","fee":"0.35","credit":"0.35","amount":"0.1","receive":"1500","parent_id":"1","super_id":"1","client_id":"my-order","ts":1702543272000}]}`,
+	})
+
+	api := bitkub.NewClient("key", "secret", bitkub.WithBaseURL(srv.URL)).V3()
+	ctx := context.Background()
+
+	order, err := api.PlaceBid(ctx, "btc_thb", bitkub.OrderTypeLimit, decimal.NewFromInt(1000), decimal.NewFromInt(15000), "my-order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.ID != "1" || order.ClientID != "my-order" || order.Rate.String() != "15000" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+
+	orders, err := api.GetOpenOrder(ctx, "btc_thb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || orders[0].ID != "2" || orders[0].Amount.String() != "0.1" {
+		t.Fatalf("unexpected open orders %+v", orders)
+	}
+
+	bad := bitkub.NewClient("key", "wrong", bitkub.WithBaseURL(srv.URL)).V3()
+	if _, err := bad.GetOpenOrder(ctx, "btc_thb"); err == nil {
+		t.Fatal("expected invalid signature")
+	}
+}